@@ -0,0 +1,59 @@
+package whatsapp
+
+import (
+	"encoding/json"
+	"strings"
+)
+
+/*
+BlockListChangedEvent is dispatched when the server sends the block list of the account, which happens after login and
+whenever a contact is blocked or unblocked, e.g. on the phone. Jids contains the complete block list.
+*/
+type BlockListChangedEvent struct {
+	Jids    []string
+	Added   []string
+	Removed []string
+}
+
+// parseBlockList parses a ["Blocklist",{"id":1,"blocklist":[...]}] json message.
+func parseBlockList(msg string) ([]string, bool) {
+	if !strings.HasPrefix(msg, `["Blocklist"`) {
+		return nil, false
+	}
+
+	var resp []json.RawMessage
+	if err := json.Unmarshal([]byte(msg), &resp); err != nil || len(resp) < 2 {
+		return nil, false
+	}
+
+	var list struct {
+		Blocklist []string `json:"blocklist"`
+	}
+	if err := json.Unmarshal(resp[1], &list); err != nil {
+		return nil, false
+	}
+
+	jids := make([]string, len(list.Blocklist))
+	for i, jid := range list.Blocklist {
+		jids[i] = toSWhatsAppJid(jid)
+	}
+	return jids, true
+}
+
+// BlockList queries the jids blocked by the account and stores them in the Store.
+func (wac *Conn) BlockList() ([]string, error) {
+	node, err := wac.query("blocklist", "", "", "", "", "", 0, 0)
+	if err != nil {
+		return nil, err
+	}
+
+	var jids []string
+	for _, n := range nodeChildren(node.Content) {
+		if n.Description == "user" {
+			jids = append(jids, toSWhatsAppJid(n.Attributes["jid"]))
+		}
+	}
+
+	wac.Store.setBlockList(jids)
+	return jids, nil
+}
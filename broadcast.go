@@ -0,0 +1,239 @@
+package whatsapp
+
+import (
+	"crypto/rand"
+	"fmt"
+	"math/big"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/Rhymen/go-whatsapp/binary"
+)
+
+// broadcastTrackingTime is the time the delivery status of a message sent to a broadcast list is kept.
+const broadcastTrackingTime = 24 * time.Hour
+
+// BroadcastList is a broadcast list of the account.
+type BroadcastList struct {
+	Jid        string
+	Name       string
+	Recipients []string
+}
+
+// BroadcastLists queries the broadcast lists of the account including their recipients.
+func (wac *Conn) BroadcastLists() ([]BroadcastList, error) {
+	node, err := wac.query("broadcast", "", "", "", "", "", 0, 0)
+	if err != nil {
+		return nil, err
+	}
+
+	var lists []BroadcastList
+	for _, n := range nodeChildren(node.Content) {
+		if n.Description != "broadcast" {
+			continue
+		}
+		list := BroadcastList{
+			Jid:  n.Attributes["jid"],
+			Name: n.Attributes["name"],
+		}
+		for _, r := range nodeChildren(n.Content) {
+			if r.Description == "recipient" {
+				list.Recipients = append(list.Recipients, toSWhatsAppJid(r.Attributes["jid"]))
+			}
+		}
+		lists = append(lists, list)
+	}
+
+	return lists, nil
+}
+
+// CreateBroadcastList creates a new broadcast list with the given recipients.
+func (wac *Conn) CreateBroadcastList(name string, recipients []string) (BroadcastList, error) {
+	jid, err := newBroadcastJid()
+	if err != nil {
+		return BroadcastList{}, err
+	}
+	list := BroadcastList{
+		Jid:        jid,
+		Name:       name,
+		Recipients: recipients,
+	}
+
+	err = wac.setBroadcastList("create", list.Jid, map[string]string{"name": name}, recipients)
+	if err != nil {
+		return BroadcastList{}, err
+	}
+	return list, nil
+}
+
+// newBroadcastJid returns a jid for a new broadcast list, the random suffix keeps lists created in the same second apart.
+func newBroadcastJid() (string, error) {
+	n, err := rand.Int(rand.Reader, big.NewInt(1000000))
+	if err != nil {
+		return "", err
+	}
+	return fmt.Sprintf("%d%06d@broadcast", time.Now().Unix(), n), nil
+}
+
+// RenameBroadcastList changes the name of the broadcast list.
+func (wac *Conn) RenameBroadcastList(jid, name string) error {
+	return wac.setBroadcastList("modify", jid, map[string]string{"name": name}, nil)
+}
+
+// AddBroadcastRecipients adds recipients to the broadcast list.
+func (wac *Conn) AddBroadcastRecipients(jid string, recipients []string) error {
+	return wac.setBroadcastList("add", jid, nil, recipients)
+}
+
+// RemoveBroadcastRecipients removes recipients from the broadcast list.
+func (wac *Conn) RemoveBroadcastRecipients(jid string, recipients []string) error {
+	return wac.setBroadcastList("remove", jid, nil, recipients)
+}
+
+// DeleteBroadcastList deletes the broadcast list.
+func (wac *Conn) DeleteBroadcastList(jid string) error {
+	return wac.setBroadcastList("delete", jid, nil, nil)
+}
+
+func (wac *Conn) setBroadcastList(t, jid string, attrs map[string]string, recipients []string) error {
+	ts := time.Now().Unix()
+	tag := fmt.Sprintf("%d.--%d", ts, wac.msgCount)
+
+	b := binary.Node{
+		Description: "broadcast",
+		Attributes: map[string]string{
+			"type": t,
+			"jid":  jid,
+		},
+	}
+	for k, v := range attrs {
+		b.Attributes[k] = v
+	}
+	if len(recipients) > 0 {
+		r := make([]binary.Node, len(recipients))
+		for i, recipient := range recipients {
+			r[i] = binary.Node{
+				Description: "recipient",
+				Attributes: map[string]string{
+					"jid": recipient,
+				},
+			}
+		}
+		b.Content = r
+	}
+
+	n := binary.Node{
+		Description: "action",
+		Attributes: map[string]string{
+			"type":  "set",
+			"epoch": strconv.Itoa(wac.msgCount),
+		},
+		Content: []interface{}{b},
+	}
+
+	ch, err := wac.writeBinary(n, group, ackRequest, tag)
+	if err != nil {
+		return fmt.Errorf("could not send proto: %v", err)
+	}
+	return wac.waitChatResponse(ch, t+" broadcast list")
+}
+
+/*
+SendBroadcast sends the message to all recipients of the broadcast list in a single relay. The Info.RemoteJid of the
+message is set to the jid of the list. The delivery status of every recipient is tracked through the receipts sent by
+the recipients and can be retrieved with BroadcastDeliveryStatus.
+*/
+func (wac *Conn) SendBroadcast(list BroadcastList, msg interface{}) (string, error) {
+	msg, ok := withInfo(msg, func(info *MessageInfo) {
+		info.RemoteJid = list.Jid
+	})
+	if !ok {
+		return "ERROR", fmt.Errorf("cannot broadcast type %T", msg)
+	}
+
+	id, err := wac.send(msg, list.Recipients)
+	if err != nil {
+		return id, err
+	}
+
+	wac.broadcasts.track(id, list.Recipients)
+	return id, nil
+}
+
+/*
+BroadcastDeliveryStatus returns the delivery status of every recipient of a message sent with SendBroadcast. Messages
+are tracked for 24 hours.
+*/
+func (wac *Conn) BroadcastDeliveryStatus(id string) (map[string]MessageStatus, bool) {
+	return wac.broadcasts.status(id)
+}
+
+type broadcastTracker struct {
+	sync.Mutex
+	m map[string]*trackedBroadcast
+}
+
+type trackedBroadcast struct {
+	sent       time.Time
+	recipients map[string]MessageStatus
+}
+
+func (bt *broadcastTracker) track(id string, recipients []string) {
+	bt.Lock()
+	defer bt.Unlock()
+
+	if bt.m == nil {
+		bt.m = make(map[string]*trackedBroadcast)
+	}
+	for k, v := range bt.m {
+		if time.Since(v.sent) > broadcastTrackingTime {
+			delete(bt.m, k)
+		}
+	}
+
+	t := &trackedBroadcast{
+		sent:       time.Now(),
+		recipients: make(map[string]MessageStatus, len(recipients)),
+	}
+	for _, r := range recipients {
+		t.recipients[r] = ServerAck
+	}
+	bt.m[id] = t
+}
+
+func (bt *broadcastTracker) update(receipt ReceiptEvent) {
+	bt.Lock()
+	defer bt.Unlock()
+
+	recipient := receipt.Participant
+	if recipient == "" {
+		recipient = receipt.RemoteJid
+	}
+
+	for _, id := range receipt.Ids {
+		t, ok := bt.m[id]
+		if !ok {
+			continue
+		}
+		if status, ok := t.recipients[recipient]; ok && receipt.Status > status {
+			t.recipients[recipient] = receipt.Status
+		}
+	}
+}
+
+func (bt *broadcastTracker) status(id string) (map[string]MessageStatus, bool) {
+	bt.Lock()
+	defer bt.Unlock()
+
+	t, ok := bt.m[id]
+	if !ok {
+		return nil, false
+	}
+
+	status := make(map[string]MessageStatus, len(t.recipients))
+	for k, v := range t.recipients {
+		status[k] = v
+	}
+	return status, true
+}
@@ -0,0 +1,73 @@
+package whatsapp
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestBroadcastDeliveryTracking(t *testing.T) {
+	var bt broadcastTracker
+	bt.track("3EB0AB", []string{"4912345@s.whatsapp.net", "4967890@s.whatsapp.net"})
+
+	receipts := []string{
+		`["Msg",{"cmd":"ack","id":"3EB0AB","ack":3,"from":"4912345@c.us","to":"4900000@c.us","t":1600000000}]`,
+		`["MsgInfo",{"cmd":"acks","id":["3EB0AB","3EB0CD"],"ack":4,"from":"123@broadcast","to":"4900000@c.us","participant":"4967890@c.us","t":1600000001}]`,
+		// receipts never downgrade the status
+		`["Msg",{"cmd":"ack","id":"3EB0AB","ack":2,"from":"4967890@c.us","to":"4900000@c.us","t":1600000002}]`,
+	}
+	for _, r := range receipts {
+		receipt, ok := parseReceipt(r)
+		if !ok {
+			t.Fatalf("could not parse receipt %s", r)
+		}
+		bt.update(receipt)
+	}
+
+	status, ok := bt.status("3EB0AB")
+	if !ok {
+		t.Fatal("message is not tracked")
+	}
+	if status["4912345@s.whatsapp.net"] != Read || status["4967890@s.whatsapp.net"] != Played {
+		t.Errorf("unexpected delivery status %v", status)
+	}
+
+	if r, ok := parseReceipt(`["Msg",{"cmd":"ack","id":"3EB0EF","ack":-1,"from":"4912345@c.us","t":1600000003}]`); !ok || r.Status != Error {
+		t.Errorf("expected error receipt, got %v", r.Status)
+	}
+	if r, ok := parseReceipt(`["Msg",{"cmd":"ack","id":"3EB0EF","ack":1,"from":"4912345@c.us","t":1600000003}]`); !ok || r.Status != ServerAck {
+		t.Errorf("expected server ack, got %v", r.Status)
+	}
+
+	if _, ok := parseReceipt(`["Msg",{"cmd":"revoke","id":"3EB0AB"}]`); ok {
+		t.Error("parsed a message that is no receipt")
+	}
+}
+
+func TestNewBroadcastJid(t *testing.T) {
+	seen := make(map[string]bool)
+	for i := 0; i < 10; i++ {
+		jid, err := newBroadcastJid()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !strings.HasSuffix(jid, "@broadcast") {
+			t.Fatalf("unexpected jid %q", jid)
+		}
+		if seen[jid] {
+			t.Fatalf("duplicate jid %q", jid)
+		}
+		seen[jid] = true
+	}
+}
+
+func TestWithInfoSetsRemoteJid(t *testing.T) {
+	msg, ok := withInfo(TextMessage{Text: "hi"}, func(info *MessageInfo) {
+		info.RemoteJid = "123@broadcast"
+	})
+	if !ok || msg.(TextMessage).Info.RemoteJid != "123@broadcast" || msg.(TextMessage).Text != "hi" {
+		t.Fatalf("unexpected message %#v", msg)
+	}
+	if _, ok := withInfo(BatteryMessage{}, func(*MessageInfo) {}); ok {
+		t.Fatal("expected events without MessageInfo to be rejected")
+	}
+}
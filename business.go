@@ -0,0 +1,359 @@
+package whatsapp
+
+import (
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// BusinessProfile contains the public profile of a WhatsApp Business account.
+type BusinessProfile struct {
+	Jid         string
+	Description string
+	Categories  []BusinessCategory
+	Hours       *BusinessHours
+	Websites    []string
+	Email       string
+	Address     string
+}
+
+// BusinessCategory is a category of a business, e.g. "Restaurant".
+type BusinessCategory struct {
+	Id   string
+	Name string
+}
+
+// BusinessHours are the opening hours of a business. Days is empty if the business has not set opening hours.
+type BusinessHours struct {
+	TimeZone string
+	Days     []BusinessDayHours
+}
+
+/*
+BusinessDayHours are the opening hours of a business on one day of the week. Mode is "specific_hours", "open_24h" or
+"appointment_only". OpenTime and CloseTime are the minutes after midnight and only set for specific hours.
+*/
+type BusinessDayHours struct {
+	Day       string
+	Mode      string
+	OpenTime  int
+	CloseTime int
+}
+
+type businessProfileResponse struct {
+	Status   int `json:"status"`
+	Profiles []struct {
+		Wid     string `json:"wid"`
+		Profile struct {
+			Description string `json:"description"`
+			Categories  []struct {
+				Id   string `json:"id"`
+				Name string `json:"localized_display_name"`
+			} `json:"categories"`
+			BusinessHours *struct {
+				TimeZone string `json:"timezone"`
+				Config   []struct {
+					Day       string     `json:"day_of_week"`
+					Mode      string     `json:"mode"`
+					OpenTime  jsonNumber `json:"open_time"`
+					CloseTime jsonNumber `json:"close_time"`
+				} `json:"config"`
+			} `json:"business_hours"`
+			Website []string `json:"website"`
+			Email   string   `json:"email"`
+			Address string   `json:"address"`
+		} `json:"profile"`
+	} `json:"profiles"`
+}
+
+func (r *businessProfileResponse) profile(jid string) *BusinessProfile {
+	if len(r.Profiles) == 0 {
+		return nil
+	}
+	p := r.Profiles[0].Profile
+
+	profile := &BusinessProfile{
+		Jid:         toSWhatsAppJid(r.Profiles[0].Wid),
+		Description: p.Description,
+		Websites:    p.Website,
+		Email:       p.Email,
+		Address:     p.Address,
+	}
+	if profile.Jid == "" {
+		profile.Jid = jid
+	}
+	for _, c := range p.Categories {
+		profile.Categories = append(profile.Categories, BusinessCategory{Id: c.Id, Name: c.Name})
+	}
+	if p.BusinessHours != nil {
+		profile.Hours = &BusinessHours{TimeZone: p.BusinessHours.TimeZone}
+		for _, d := range p.BusinessHours.Config {
+			profile.Hours.Days = append(profile.Hours.Days, BusinessDayHours{
+				Day:       d.Day,
+				Mode:      d.Mode,
+				OpenTime:  int(d.OpenTime),
+				CloseTime: int(d.CloseTime),
+			})
+		}
+	}
+
+	return profile
+}
+
+// GetBusinessProfile returns the business profile of the account. It returns nil if the account is no business account.
+func (wac *Conn) GetBusinessProfile(jid string) (*BusinessProfile, error) {
+	ch, err := wac.BusinessProfile(jid)
+	if err != nil {
+		return nil, err
+	}
+
+	var resp businessProfileResponse
+	if err := wac.waitJsonResponse(ch, "business profile", &resp); err != nil {
+		return nil, err
+	}
+	if resp.Status == 404 {
+		return nil, nil
+	}
+	if resp.Status != 0 && resp.Status != 200 {
+		return nil, &ServerError{Op: "business profile request", Status: resp.Status}
+	}
+
+	return resp.profile(jid), nil
+}
+
+/*
+Product is a product of a business catalog. Price1000 is the price multiplied by 1000 in the given currency, as in the
+product snapshots of ProductMessages. Images contains the urls of all product images, the first one is the main image.
+*/
+type Product struct {
+	Id           string
+	Name         string
+	Description  string
+	Price1000    int64
+	Currency     string
+	RetailerId   string
+	URL          string
+	Images       []string
+	Hidden       bool
+	Availability string
+}
+
+/*
+Catalog is a page of a business catalog. Next is the cursor of the following page to be passed to GetCatalog, it is
+empty on the last page.
+*/
+type Catalog struct {
+	Jid      string
+	Products []Product
+	Next     string
+}
+
+type productResponse struct {
+	Id           string     `json:"id"`
+	Name         string     `json:"name"`
+	Description  string     `json:"description"`
+	Price        jsonNumber `json:"price"`
+	Currency     string     `json:"currency"`
+	RetailerId   string     `json:"retailer_id"`
+	URL          string     `json:"url"`
+	IsHidden     bool       `json:"is_hidden"`
+	Availability string     `json:"availability"`
+	Images       []cdnURL   `json:"image_cdn_urls"`
+	MoreImages   [][]cdnURL `json:"additional_image_cdn_urls"`
+}
+
+type cdnURL struct {
+	Key   string `json:"key"`
+	Value string `json:"value"`
+}
+
+// requestedURL returns the url of the image in the requested size, or the first url if there is none.
+func requestedURL(urls []cdnURL) string {
+	for _, u := range urls {
+		if u.Key == "requested" {
+			return u.Value
+		}
+	}
+	if len(urls) > 0 {
+		return urls[0].Value
+	}
+	return ""
+}
+
+func (r *productResponse) product() Product {
+	p := Product{
+		Id:           r.Id,
+		Name:         r.Name,
+		Description:  r.Description,
+		Price1000:    int64(r.Price),
+		Currency:     r.Currency,
+		RetailerId:   r.RetailerId,
+		URL:          r.URL,
+		Hidden:       r.IsHidden,
+		Availability: r.Availability,
+	}
+	if u := requestedURL(r.Images); u != "" {
+		p.Images = append(p.Images, u)
+	}
+	for _, urls := range r.MoreImages {
+		if u := requestedURL(urls); u != "" {
+			p.Images = append(p.Images, u)
+		}
+	}
+	return p
+}
+
+type catalogResponse struct {
+	Status int               `json:"status"`
+	Data   []productResponse `json:"data"`
+	Paging struct {
+		Cursors struct {
+			After string `json:"after"`
+		} `json:"cursors"`
+	} `json:"paging"`
+}
+
+/*
+GetCatalog returns a page of the product catalog of a business. limit is the maximum number of products on the page,
+after is the Next cursor of the previous page or empty for the first page. Product images are requested with a size of
+imageSize pixels.
+*/
+func (wac *Conn) GetCatalog(jid string, limit int, after string, imageSize int) (*Catalog, error) {
+	query := map[string]string{
+		"catalogWid":      strings.Replace(jid, "@s.whatsapp.net", "@c.us", 1),
+		"limit":           strconv.Itoa(limit),
+		"height":          strconv.Itoa(imageSize),
+		"width":           strconv.Itoa(imageSize),
+		"allowShopSource": "false",
+	}
+	if after != "" {
+		query["after"] = after
+	}
+
+	ch, err := wac.writeJson([]interface{}{"query", "bizCatalog", query})
+	if err != nil {
+		return nil, err
+	}
+
+	var resp catalogResponse
+	if err := wac.waitJsonResponse(ch, "catalog", &resp); err != nil {
+		return nil, err
+	}
+	if resp.Status != 0 && resp.Status != 200 {
+		return nil, &ServerError{Op: "catalog request", Status: resp.Status}
+	}
+
+	catalog := &Catalog{Jid: jid}
+	for _, p := range resp.Data {
+		catalog.Products = append(catalog.Products, p.product())
+	}
+	if len(resp.Data) == limit {
+		catalog.Next = resp.Paging.Cursors.After
+	}
+
+	return catalog, nil
+}
+
+/*
+Order contains the details of an order. All amounts are multiplied by 1000 in the currency of the order. Subtotal and
+Tax are zero if the business did not provide them.
+*/
+type Order struct {
+	Id           string
+	Items        []OrderItem
+	Subtotal1000 int64
+	Tax1000      int64
+	Total1000    int64
+	Currency     string
+}
+
+// OrderItem is a line item of an Order. Price1000 is the price of a single unit.
+type OrderItem struct {
+	ProductId string
+	Name      string
+	ImageURL  string
+	Price1000 int64
+	Currency  string
+	Quantity  int
+}
+
+// Amount1000 returns the total price of the line item.
+func (i OrderItem) Amount1000() int64 {
+	return i.Price1000 * int64(i.Quantity)
+}
+
+type orderResponse struct {
+	Status int `json:"status"`
+	Data   struct {
+		Price struct {
+			Subtotal jsonNumber `json:"subtotal"`
+			Tax      jsonNumber `json:"tax"`
+			Total    jsonNumber `json:"total"`
+			Currency string     `json:"currency"`
+		} `json:"price"`
+		Products []struct {
+			Id       string     `json:"id"`
+			Name     string     `json:"name"`
+			Price    jsonNumber `json:"price"`
+			Currency string     `json:"currency"`
+			Quantity jsonNumber `json:"quantity"`
+			Image    struct {
+				URL string `json:"url"`
+			} `json:"image"`
+		} `json:"products"`
+	} `json:"data"`
+}
+
+func (r *orderResponse) order(orderId string) *Order {
+	order := &Order{
+		Id:           orderId,
+		Subtotal1000: int64(r.Data.Price.Subtotal),
+		Tax1000:      int64(r.Data.Price.Tax),
+		Total1000:    int64(r.Data.Price.Total),
+		Currency:     r.Data.Price.Currency,
+	}
+	for _, p := range r.Data.Products {
+		order.Items = append(order.Items, OrderItem{
+			ProductId: p.Id,
+			Name:      p.Name,
+			ImageURL:  p.Image.URL,
+			Price1000: int64(p.Price),
+			Currency:  p.Currency,
+			Quantity:  int(p.Quantity),
+		})
+	}
+	return order
+}
+
+// GetOrder returns the details of the order of an OrderMessage.
+func (wac *Conn) GetOrder(msg OrderMessage) (*Order, error) {
+	tag := fmt.Sprintf("%d.--%d", time.Now().Unix(), wac.msgCount)
+	ch, err := wac.SearchProductDetails(tag, msg.OrderId, msg.Token)
+	if err != nil {
+		return nil, err
+	}
+
+	var resp orderResponse
+	if err := wac.waitJsonResponse(ch, "order", &resp); err != nil {
+		return nil, err
+	}
+	if resp.Status != 0 && resp.Status != 200 {
+		return nil, &ServerError{Op: "order request", Status: resp.Status}
+	}
+
+	return resp.order(msg.OrderId), nil
+}
+
+func (wac *Conn) waitJsonResponse(ch <-chan string, op string, v interface{}) error {
+	select {
+	case r := <-ch:
+		if err := json.Unmarshal([]byte(r), v); err != nil {
+			return fmt.Errorf("error decoding %s response: %v", op, err)
+		}
+	case <-time.After(wac.msgTimeout):
+		return &TimeoutError{Op: op + " request"}
+	}
+	return nil
+}
@@ -0,0 +1,59 @@
+package whatsapp
+
+import (
+	"encoding/json"
+	"testing"
+)
+
+func TestCatalogResponse(t *testing.T) {
+	data := `{"status":200,"data":[{"id":"123","name":"Coffee","price":"2500","currency":"EUR","retailer_id":"c-1",
+		"image_cdn_urls":[{"key":"original","value":"https://a/o"},{"key":"requested","value":"https://a/r"}],
+		"additional_image_cdn_urls":[[{"key":"requested","value":"https://b/r"}]]}],
+		"paging":{"cursors":{"before":"x","after":"y"}}}`
+
+	var resp catalogResponse
+	if err := json.Unmarshal([]byte(data), &resp); err != nil {
+		t.Fatal(err)
+	}
+	if len(resp.Data) != 1 || resp.Paging.Cursors.After != "y" {
+		t.Fatalf("unexpected response %+v", resp)
+	}
+
+	p := resp.Data[0].product()
+	if p.Id != "123" || p.Price1000 != 2500 || p.Currency != "EUR" || p.RetailerId != "c-1" {
+		t.Errorf("unexpected product %+v", p)
+	}
+	if len(p.Images) != 2 || p.Images[0] != "https://a/r" || p.Images[1] != "https://b/r" {
+		t.Errorf("unexpected product images %v", p.Images)
+	}
+}
+
+func TestOrderResponse(t *testing.T) {
+	data := `{"status":200,"data":{"price":{"subtotal":"5000","total":5500,"currency":"EUR"},
+		"products":[{"id":"123","name":"Coffee","price":"2500","currency":"EUR","quantity":2,"image":{"url":"https://a"}}]}}`
+
+	var resp orderResponse
+	if err := json.Unmarshal([]byte(data), &resp); err != nil {
+		t.Fatal(err)
+	}
+
+	o := resp.order("42")
+	if o.Id != "42" || o.Subtotal1000 != 5000 || o.Total1000 != 5500 || o.Tax1000 != 0 || o.Currency != "EUR" {
+		t.Errorf("unexpected order %+v", o)
+	}
+	if len(o.Items) != 1 || o.Items[0].Quantity != 2 || o.Items[0].Amount1000() != 5000 || o.Items[0].ImageURL != "https://a" {
+		t.Errorf("unexpected order items %+v", o.Items)
+	}
+}
+
+func TestJsonNumber(t *testing.T) {
+	var v struct {
+		A, B, C, D jsonNumber
+	}
+	if err := json.Unmarshal([]byte(`{"A":12,"B":"34","C":"","D":9007199254740993}`), &v); err != nil {
+		t.Fatal(err)
+	}
+	if v.A != 12 || v.B != 34 || v.C != 0 || v.D != 9007199254740993 {
+		t.Errorf("unexpected numbers %+v", v)
+	}
+}
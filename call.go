@@ -0,0 +1,102 @@
+package whatsapp
+
+import (
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/Rhymen/go-whatsapp/binary"
+)
+
+// CallEventType describes the state of a call reported by a CallEvent.
+type CallEventType string
+
+const (
+	CallOffer     CallEventType = "offer"
+	CallAccept    CallEventType = "accept"
+	CallReject    CallEventType = "reject"
+	CallTimeout   CallEventType = "timeout"
+	CallTerminate CallEventType = "terminate"
+)
+
+/*
+CallEvent is dispatched for incoming calls. A call starts with a CallOffer and ends with a CallTimeout (missed),
+CallReject or CallTerminate event.
+*/
+type CallEvent struct {
+	Type      CallEventType
+	CallId    string
+	From      string
+	Creator   string
+	Video     bool
+	Timestamp time.Time
+}
+
+func getCallEvent(msg binary.Node) interface{} {
+	children := nodeChildren(msg.Content)
+	if len(children) == 0 {
+		return nil
+	}
+	c := children[0]
+
+	event := CallEvent{
+		Type:    CallEventType(c.Description),
+		CallId:  c.Attributes["call-id"],
+		From:    toSWhatsAppJid(msg.Attributes["from"]),
+		Creator: toSWhatsAppJid(c.Attributes["call-creator"]),
+	}
+	if event.Creator == "" {
+		event.Creator = event.From
+	}
+	if ts, err := strconv.ParseInt(msg.Attributes["t"], 10, 64); err == nil {
+		event.Timestamp = time.Unix(ts, 0)
+	}
+	for _, n := range nodeChildren(c.Content) {
+		if n.Description == "video" {
+			event.Video = true
+		}
+	}
+
+	return event
+}
+
+/*
+RejectCall declines an incoming call. To answer the caller, send a message to callerJid afterwards, e.g.:
+
+	if err := wac.RejectCall(call.CallId, call.From); err == nil {
+		wac.Send(whatsapp.TextMessage{Info: whatsapp.MessageInfo{RemoteJid: call.From}, Text: "Please text us instead."})
+	}
+*/
+func (wac *Conn) RejectCall(callId, callerJid string) error {
+	if wac.session == nil {
+		return ErrInvalidSession
+	}
+
+	ts := time.Now().Unix()
+	tag := fmt.Sprintf("%d.--%d", ts, wac.msgCount)
+
+	n := binary.Node{
+		Description: "call",
+		Attributes: map[string]string{
+			"from": wac.session.Wid,
+			"to":   callerJid,
+			"id":   tag,
+		},
+		Content: []interface{}{
+			binary.Node{
+				Description: "reject",
+				Attributes: map[string]string{
+					"call-id":      callId,
+					"call-creator": callerJid,
+					"count":        "0",
+				},
+			},
+		},
+	}
+
+	_, err := wac.writeBinary(n, call, ignore, tag)
+	if err != nil {
+		return fmt.Errorf("could not send call rejection: %v", err)
+	}
+	return nil
+}
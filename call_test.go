@@ -0,0 +1,57 @@
+package whatsapp
+
+import (
+	"testing"
+	"time"
+
+	"github.com/Rhymen/go-whatsapp/binary"
+)
+
+func TestGetCallEvent(t *testing.T) {
+	offer := binary.Node{
+		Description: "call",
+		Attributes:  map[string]string{"from": "4912345@c.us", "id": "1", "t": "1600000000"},
+		Content: []interface{}{
+			binary.Node{
+				Description: "offer",
+				Attributes:  map[string]string{"call-id": "ABCDEF", "call-creator": "4912345@c.us"},
+				Content:     []interface{}{binary.Node{Description: "audio"}, binary.Node{Description: "video"}},
+			},
+		},
+	}
+	event, ok := getCallEvent(offer).(CallEvent)
+	if !ok {
+		t.Fatalf("expected a CallEvent, got %T", getCallEvent(offer))
+	}
+	want := CallEvent{
+		Type:      CallOffer,
+		CallId:    "ABCDEF",
+		From:      "4912345@s.whatsapp.net",
+		Creator:   "4912345@s.whatsapp.net",
+		Video:     true,
+		Timestamp: time.Unix(1600000000, 0),
+	}
+	if event != want {
+		t.Errorf("unexpected offer %+v, want %+v", event, want)
+	}
+
+	terminate := binary.Node{
+		Description: "call",
+		Attributes:  map[string]string{"from": "4912345@c.us", "id": "2", "t": "1600000030"},
+		Content:     []binary.Node{{Description: "terminate", Attributes: map[string]string{"call-id": "ABCDEF"}}},
+	}
+	event, _ = getCallEvent(terminate).(CallEvent)
+	if event.Type != CallTerminate || event.CallId != "ABCDEF" || event.Creator != event.From || event.Video {
+		t.Errorf("unexpected terminate %+v", event)
+	}
+
+	if e := getCallEvent(binary.Node{Description: "call"}); e != nil {
+		t.Errorf("expected no event for an empty call node, got %v", e)
+	}
+}
+
+func TestRejectCallWithoutSession(t *testing.T) {
+	if err := (&Conn{}).RejectCall("ABCDEF", "4912345@s.whatsapp.net"); err != ErrInvalidSession {
+		t.Errorf("expected ErrInvalidSession, got %v", err)
+	}
+}
@@ -0,0 +1,179 @@
+package whatsapp
+
+import (
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/Rhymen/go-whatsapp/binary"
+)
+
+// ArchiveChat archives or unarchives the chat.
+func (wac *Conn) ArchiveChat(jid string, archive bool) error {
+	t := "unarchive"
+	if archive {
+		t = "archive"
+	}
+
+	err := wac.modifyChat(jid, map[string]string{"type": t}, t+" chat")
+	if err != nil {
+		return err
+	}
+
+	wac.Store.updateChat(jid, func(chat *Chat) {
+		chat.IsArchived = strconv.FormatBool(archive)
+	})
+	return nil
+}
+
+// PinChat pins or unpins the chat.
+func (wac *Conn) PinChat(jid string, pin bool) error {
+	attrs := map[string]string{"type": "pin"}
+	ts := strconv.FormatInt(time.Now().Unix(), 10)
+	if pin {
+		attrs["pin"] = ts
+	} else {
+		ts = ""
+		if chat, ok := wac.Store.GetChat(jid); ok && chat.IsPinned != "" {
+			attrs["previous"] = chat.IsPinned
+		}
+	}
+
+	if err := wac.modifyChat(jid, attrs, "pin chat"); err != nil {
+		return err
+	}
+
+	wac.Store.updateChat(jid, func(chat *Chat) {
+		chat.IsPinned = ts
+	})
+	return nil
+}
+
+// MuteChat mutes the chat until the given time. A zero time unmutes the chat.
+func (wac *Conn) MuteChat(jid string, until time.Time) error {
+	attrs := map[string]string{"type": "mute"}
+	mute := ""
+	if !until.IsZero() {
+		mute = strconv.FormatInt(until.Unix(), 10)
+		attrs["mute"] = mute
+	} else if chat, ok := wac.Store.GetChat(jid); ok && chat.IsMuted != "" {
+		attrs["previous"] = chat.IsMuted
+	}
+
+	if err := wac.modifyChat(jid, attrs, "mute chat"); err != nil {
+		return err
+	}
+
+	wac.Store.updateChat(jid, func(chat *Chat) {
+		chat.IsMuted = mute
+	})
+	return nil
+}
+
+/*
+MarkUnread marks the chat as unread. The chat is marked as read again by a call to Read. Like on the phone, a marked
+chat has an unread count of -1.
+*/
+func (wac *Conn) MarkUnread(jid string) error {
+	ts := time.Now().Unix()
+	tag := fmt.Sprintf("%d.--%d", ts, wac.msgCount)
+
+	n := binary.Node{
+		Description: "action",
+		Attributes: map[string]string{
+			"type":  "set",
+			"epoch": strconv.Itoa(wac.msgCount),
+		},
+		Content: []interface{}{binary.Node{
+			Description: "read",
+			Attributes: map[string]string{
+				"jid":   jid,
+				"count": "-2",
+				"type":  "false",
+			},
+		}},
+	}
+
+	ch, err := wac.writeBinary(n, read, ignore, tag)
+	if err != nil {
+		return fmt.Errorf("could not send proto: %v", err)
+	}
+	if err := wac.waitChatResponse(ch, "mark chat unread"); err != nil {
+		return err
+	}
+
+	wac.Store.updateChat(jid, func(chat *Chat) {
+		chat.Unread = "-1"
+	})
+	return nil
+}
+
+// ClearChat deletes all messages of the chat. Starred messages are kept if keepStarred is set.
+func (wac *Conn) ClearChat(jid string, keepStarred bool) error {
+	attrs := map[string]string{
+		"type": "clear",
+		"star": strconv.FormatBool(!keepStarred),
+	}
+
+	if err := wac.modifyChat(jid, attrs, "clear chat"); err != nil {
+		return err
+	}
+
+	wac.Store.updateChat(jid, func(chat *Chat) {
+		chat.Unread = "0"
+	})
+	return nil
+}
+
+// DeleteChat deletes the chat including all its messages and removes it from the Store.
+func (wac *Conn) DeleteChat(jid string) error {
+	if err := wac.modifyChat(jid, map[string]string{"type": "delete"}, "delete chat"); err != nil {
+		return err
+	}
+
+	wac.Store.removeChat(jid)
+	return nil
+}
+
+func (wac *Conn) modifyChat(jid string, attrs map[string]string, op string) error {
+	tag := fmt.Sprintf("%s.--%d", wac.timeTag, wac.msgCount)
+
+	attrs["jid"] = jid
+	n := binary.Node{
+		Description: "action",
+		Attributes: map[string]string{
+			"epoch": strconv.Itoa(wac.msgCount),
+			"type":  "set",
+		},
+		Content: []interface{}{
+			binary.Node{
+				Description: "chat",
+				Attributes:  attrs,
+			},
+		},
+	}
+
+	ch, err := wac.writeBinary(n, chat, expires|skipOffline, tag)
+	if err != nil {
+		return fmt.Errorf("could not send proto: %v", err)
+	}
+	return wac.waitChatResponse(ch, op)
+}
+
+func (wac *Conn) waitChatResponse(ch <-chan string, op string) error {
+	select {
+	case response := <-ch:
+		var resp map[string]interface{}
+		if err := json.Unmarshal([]byte(response), &resp); err != nil {
+			return fmt.Errorf("error decoding %s response: %v", op, err)
+		}
+		if status, ok := resp["status"].(float64); ok && int(status) != 200 {
+			return &ServerError{Op: op, Status: int(status)}
+		}
+	case <-time.After(wac.msgTimeout):
+		return &TimeoutError{Op: op}
+	}
+
+	return nil
+}
@@ -1,6 +1,8 @@
 package whatsapp
 
 import (
+	"errors"
+
 	"github.com/Rhymen/go-whatsapp/binary"
 	"github.com/Rhymen/go-whatsapp/binary/proto"
 	"log"
@@ -42,10 +44,6 @@ func (wac *Conn) LoadChatMessages(jid string, count int, messageId string, owner
 		return nil
 	}
 
-	if handlers == nil {
-		handlers = wac.handler
-	}
-
 	kind := "before"
 	if after {
 		kind = "after"
@@ -76,10 +74,6 @@ func (wac *Conn) LoadFullChatHistory(jid string, chunkSize int,
 		return
 	}
 
-	if handlers == nil {
-		handlers = wac.handler
-	}
-
 	beforeMsg := ""
 	beforeMsgIsOwner := true
 
@@ -120,10 +114,6 @@ func (wac *Conn) LoadFullChatHistoryAfter(jid string, messageId string, chunkSiz
 		return
 	}
 
-	if handlers == nil {
-		handlers = wac.handler
-	}
-
 	msgOwner := true
 	prevNotFound := false
 
@@ -134,7 +124,7 @@ func (wac *Conn) LoadFullChatHistoryAfter(jid string, messageId string, chunkSiz
 		if err != nil {
 
 			// Whatsapp will return 404 status when there is wrong owner flag on the requested message id
-			if err == ErrServerRespondedWith404 {
+			if errors.Is(err, ErrServerRespondedWith404) {
 
 				// this will detect two consecutive "not found" errors.
 				// this is done to prevent infinite loop when wrong message id supplied
@@ -79,14 +79,28 @@ type Conn struct {
 	loggedIn  bool
 	wg        *sync.WaitGroup
 
-	session        *Session
-	sessionLock    uint32
-	handler        []Handler
-	msgCount       int
-	msgTimeout     time.Duration
-	Info           *Info
-	Store          *Store
-	ServerLastSeen time.Time
+	session            *Session
+	sessionLock        uint32
+	handler            []Handler
+	events             eventBus
+	dispatcher         *orderedDispatcher
+	middleware         []Middleware
+	loginTime          time.Time
+	logger             Logger
+	logNodes           bool
+	recorder           *Recorder
+	metrics            Metrics
+	connections        int
+	msgCount           int
+	msgTimeout         time.Duration
+	groupMetadataTTL   time.Duration
+	expandQuickReplies bool
+	refuseBlocked      bool
+	broadcasts         broadcastTracker
+	outbox             outboxRef
+	Info               *Info
+	Store              *Store
+	ServerLastSeen     time.Time
 
 	timeTag string // last 3 digits obtained after a successful login takeover
 
@@ -108,7 +122,8 @@ type websocketWrapper struct {
 
 type listenerWrapper struct {
 	sync.RWMutex
-	m map[string]chan string
+	m        map[string]chan string
+	requests map[string]pendingRequest
 }
 
 /*
@@ -138,19 +153,46 @@ type Options struct {
 	LongClientName   string
 	ClientVersion    string
 	Store            *Store
+	// GroupMetadataTTL is the time GroupInfo uses cached group metadata before requesting it again.
+	GroupMetadataTTL time.Duration
+	// ExpandQuickReplies enables replacing quick reply shortcuts in sent text messages, see Store.ExpandQuickReply.
+	ExpandQuickReplies bool
+	// RefuseBlocked makes Send return an *ErrBlocked instead of messaging jids on the block list.
+	RefuseBlocked bool
+	// DispatchWorkers enables ordered dispatch: asynchronous handler calls are run on this many workers instead of a
+	// goroutine per call, calls for the same chat are run in the order the events were received. The workers are stopped
+	// by Disconnect and started again when connecting.
+	DispatchWorkers int
+	// DispatchQueueSize limits the number of pending handler calls in ordered dispatch, further events are dropped.
+	DispatchQueueSize int
+	// Logger receives the log entries of the connection, nothing is logged if it is nil.
+	Logger Logger
+	// LogNodes enables debug logging of the sent and received binary nodes, with sensitive content redacted.
+	LogNodes bool
+	// Recorder records all frames sent and received, see NewReplayer for replaying them.
+	Recorder *Recorder
+	// Metrics receives measurements of the connection, e.g. NewExpvarMetrics. Nothing is measured if it is nil.
+	Metrics Metrics
 }
 func NewConnWithOptions(opt *Options) (*Conn, error) {
 	if opt == nil {
 		return nil, ErrOptionsNotProvided
 	}
+	wac := newConn(opt)
+	return wac, wac.connect()
+}
+
+func newConn(opt *Options) *Conn {
 	wac := &Conn{
-		handler:    make([]Handler, 0),
-		msgCount:   0,
-		msgTimeout: opt.Timeout,
-		Store:      newStore(),
-		longClientName:  "github.com/Rhymen/go-whatsapp",
-		shortClientName: "go-whatsapp",
-		clientVersion:   "0.1.0",
+		handler:          make([]Handler, 0),
+		msgCount:         0,
+		msgTimeout:       opt.Timeout,
+		Store:            newStore(),
+		longClientName:   "github.com/Rhymen/go-whatsapp",
+		shortClientName:  "go-whatsapp",
+		clientVersion:    "0.1.0",
+		groupMetadataTTL: defaultGroupMetadataTTL,
+		middleware:       []Middleware{Recover()},
 	}
 	if opt.Handler != nil {
 		wac.handler = opt.Handler
@@ -170,7 +212,19 @@ func NewConnWithOptions(opt *Options) (*Conn, error) {
 	if len(opt.ClientVersion) != 0 {
 		wac.clientVersion = opt.ClientVersion
 	}
-	return wac, wac.connect()
+	if opt.GroupMetadataTTL != 0 {
+		wac.groupMetadataTTL = opt.GroupMetadataTTL
+	}
+	wac.expandQuickReplies = opt.ExpandQuickReplies
+	wac.refuseBlocked = opt.RefuseBlocked
+	wac.logger = opt.Logger
+	wac.logNodes = opt.LogNodes
+	if opt.DispatchWorkers > 0 {
+		wac.dispatcher = newOrderedDispatcher(opt.DispatchWorkers, opt.DispatchQueueSize, wac.reportDispatchError)
+	}
+	wac.recorder = opt.Recorder
+	wac.metrics = opt.Metrics
+	return wac
 }
 
 // connect should be guarded with wsWriteMutex
@@ -192,9 +246,11 @@ func (wac *Conn) connect() (err error) {
 		Proxy:            wac.Proxy,
 	}
 
+	wac.log(LogInfo, "connecting")
 	headers := http.Header{"Origin": []string{"https://web.whatsapp.com"}}
 	wsConn, _, err := dialer.Dial("wss://web.whatsapp.com/ws", headers)
 	if err != nil {
+		wac.log(LogError, "connecting failed", "err", err)
 		return errors.Wrap(err, "couldn't dial whatsapp web websocket")
 	}
 
@@ -204,6 +260,7 @@ func (wac *Conn) connect() (err error) {
 		err := wsConn.WriteControl(websocket.CloseMessage, message, time.Now().Add(time.Second))
 
 		// our close handling
+		wac.log(LogWarn, "connection closed by server", "code", code, "text", text)
 		_, _ = wac.Disconnect()
 		wac.handle(&ErrConnectionClosed{Code: code, Text: text})
 		return err
@@ -218,12 +275,21 @@ func (wac *Conn) connect() (err error) {
 		m: make(map[string]chan string),
 	}
 
+	if wac.dispatcher != nil {
+		wac.dispatcher.start()
+	}
+
 	wac.wg = &sync.WaitGroup{}
 	wac.wg.Add(2)
 	go wac.readPump()
 	go wac.keepAlive(20000, 60000)
 
 	wac.loggedIn = false
+	wac.log(LogInfo, "connected")
+	if wac.connections > 0 {
+		wac.count(MetricReconnects, 1)
+	}
+	wac.connections++
 	return nil
 }
 
@@ -233,12 +299,16 @@ func (wac *Conn) Disconnect() (Session, error) {
 	}
 	wac.connected = false
 	wac.loggedIn = false
+	if wac.dispatcher != nil {
+		wac.dispatcher.stop()
+	}
 
 	close(wac.ws.close) //signal close
 	wac.wg.Wait()       //wait for close
 
 	err := wac.ws.conn.Close()
 	wac.ws = nil
+	wac.log(LogInfo, "disconnected")
 
 	if wac.session == nil {
 		return Session{}, err
@@ -265,6 +335,7 @@ func (wac *Conn) keepAlive(minIntervalMs int, maxIntervalMs int) {
 	for {
 		err := wac.sendKeepAlive()
 		if err != nil {
+			wac.log(LogWarn, "keep alive failed", "err", err)
 			wac.handle(errors.Wrap(err, "keepAlive failed"))
 			//TODO: Consequences?
 		}
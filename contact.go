@@ -170,8 +170,17 @@ func (wac *Conn) query(t, jid, messageId, kind, owner, search string, count, pag
 	}
 
 	metric := group
-	if t == "media" {
+	switch t {
+	case "media":
 		metric = queryMedia
+	case "label":
+		metric = queryLabels
+	case "quick_reply":
+		metric = queryQuickReplies
+	case "privacy":
+		metric = privacyStatus
+	case "blocklist":
+		metric = block
 	}
 
 	ch, err := wac.writeBinary(n, metric, ignore, tag)
@@ -179,9 +188,14 @@ func (wac *Conn) query(t, jid, messageId, kind, owner, search string, count, pag
 		return nil, err
 	}
 
-	msg, err := wac.decryptBinaryMessage([]byte(<-ch))
-	if err != nil {
-		return nil, err
+	var msg *binary.Node
+	select {
+	case r := <-ch:
+		if msg, err = wac.decryptBinaryMessage([]byte(r)); err != nil {
+			return nil, err
+		}
+	case <-time.After(wac.msgTimeout):
+		return nil, &TimeoutError{Op: "query " + t}
 	}
 
 	//TODO: use parseProtoMessage
@@ -189,12 +203,13 @@ func (wac *Conn) query(t, jid, messageId, kind, owner, search string, count, pag
 }
 
 func (wac *Conn) setGroup(t, jid, subject string, participants []string) (<-chan string, error) {
-	ts := time.Now().Unix()
-	tag := fmt.Sprintf("%d.--%d", ts, wac.msgCount)
-
 	//TODO: get proto or improve encoder to handle []interface{}
+	return wac.setGroupContent(t, jid, subject, buildParticipantNodes(participants))
+}
 
-	p := buildParticipantNodes(participants)
+func (wac *Conn) setGroupContent(t, jid, subject string, content []binary.Node) (<-chan string, error) {
+	ts := time.Now().Unix()
+	tag := fmt.Sprintf("%d.--%d", ts, wac.msgCount)
 
 	g := binary.Node{
 		Description: "group",
@@ -203,11 +218,12 @@ func (wac *Conn) setGroup(t, jid, subject string, participants []string) (<-chan
 			"id":     tag,
 			"type":   t,
 		},
-		Content: p,
+		Content: content,
 	}
 
 	if jid != "" {
 		g.Attributes["jid"] = jid
+		wac.Store.invalidateGroupMetadata(jid)
 	}
 
 	if subject != "" {
@@ -0,0 +1,199 @@
+package whatsapp
+
+import (
+	"sync"
+	"time"
+)
+
+const (
+	defaultDispatchQueueSize = 1000
+	// dropReportInterval limits how often dropped events are reported while the queue is full.
+	dropReportInterval = time.Second
+)
+
+/*
+orderedDispatcher runs asynchronous handler calls on a fixed number of workers. Calls for the same chat are run one
+after another in the order they were submitted, calls for different chats run in parallel.
+*/
+type orderedDispatcher struct {
+	sync.Mutex
+	chats   map[string][]func()
+	ready   chan string
+	pending int
+	limit   int
+	workers int
+	done    chan struct{}
+
+	congested bool
+	dropped   int
+	lastDrop  time.Time
+	report    func(err error)
+}
+
+func newOrderedDispatcher(workers, limit int, report func(err error)) *orderedDispatcher {
+	if limit <= 0 {
+		limit = defaultDispatchQueueSize
+	}
+	d := &orderedDispatcher{
+		chats:   make(map[string][]func()),
+		ready:   make(chan string, limit),
+		limit:   limit,
+		workers: workers,
+		report:  report,
+	}
+	d.start()
+	return d
+}
+
+// start starts the workers if they are not running. Queued calls are kept while the workers are stopped.
+func (d *orderedDispatcher) start() {
+	d.Lock()
+	defer d.Unlock()
+	if d.done != nil {
+		return
+	}
+	d.done = make(chan struct{})
+	for i := 0; i < d.workers; i++ {
+		go d.work(d.done)
+	}
+}
+
+// stop stops the workers after their current call.
+func (d *orderedDispatcher) stop() {
+	d.Lock()
+	defer d.Unlock()
+	if d.done != nil {
+		close(d.done)
+		d.done = nil
+	}
+}
+
+/*
+submit queues the call for the chat. If the queue is full the call is dropped and an *ErrEventDropped is reported at
+most once per second, when the queue is three quarters full ErrDispatchBackpressure is reported once until it has
+drained to half.
+*/
+func (d *orderedDispatcher) submit(chat string, event interface{}, call func()) {
+	d.Lock()
+	if d.pending >= d.limit {
+		d.dropped++
+		var dropped *ErrEventDropped
+		if time.Since(d.lastDrop) >= dropReportInterval {
+			dropped = &ErrEventDropped{Chat: chat, Event: event, Count: d.dropped}
+			d.dropped = 0
+			d.lastDrop = time.Now()
+		}
+		d.Unlock()
+		if dropped != nil {
+			d.report(dropped)
+		}
+		return
+	}
+	d.pending++
+	congested := !d.congested && d.pending >= d.limit*3/4
+	if congested {
+		d.congested = true
+	}
+	queue, scheduled := d.chats[chat]
+	d.chats[chat] = append(queue, call)
+	d.Unlock()
+
+	if !scheduled {
+		d.ready <- chat
+	}
+	if congested {
+		d.report(ErrDispatchBackpressure)
+	}
+}
+
+func (d *orderedDispatcher) work(done <-chan struct{}) {
+	for {
+		var chat string
+		select {
+		case <-done:
+			return
+		case chat = <-d.ready:
+		}
+		select {
+		case <-done:
+			// stopped while waiting, leave the chat to the next workers
+			d.ready <- chat
+			return
+		default:
+		}
+
+		d.Lock()
+		call := d.chats[chat][0]
+		d.chats[chat] = d.chats[chat][1:]
+		d.Unlock()
+
+		call()
+
+		d.Lock()
+		d.pending--
+		if d.congested && d.pending <= d.limit/2 {
+			d.congested = false
+		}
+		requeue := len(d.chats[chat]) > 0
+		if !requeue {
+			delete(d.chats, chat)
+		}
+		d.Unlock()
+
+		if requeue {
+			// let other chats run before the next call of this chat, the chat stays scheduled so submit doesn't queue it twice
+			d.ready <- chat
+		}
+	}
+}
+
+// async runs the handler call for the event in a new goroutine, or on the ordered dispatcher if it is enabled.
+func (wac *Conn) async(event interface{}, call func()) {
+	if wac.dispatcher == nil {
+		go call()
+		return
+	}
+	wac.dispatcher.submit(eventChat(event), event, call)
+}
+
+/*
+reportDispatchError passes errors of the dispatcher and middleware to the handlers without dispatching them as events,
+which could fail the same way. The handlers are called in the calling goroutine, so no goroutines pile up while the
+dispatcher is saturated, and panics of the handlers are only logged.
+*/
+func (wac *Conn) reportDispatchError(err error) {
+	wac.log(LogError, "dispatching failed", "err", err)
+	for _, h := range wac.handler {
+		wac.reportError(h, err)
+	}
+}
+
+func (wac *Conn) reportError(h Handler, err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			wac.log(LogError, "error handler panicked", "err", err, "panic", r)
+		}
+	}()
+	h.HandleError(err)
+}
+
+// eventChat returns the jid of the chat an event belongs to, or an empty string for events without a chat.
+func eventChat(event interface{}) string {
+	switch e := event.(type) {
+	case GroupEvent:
+		return e.GroupJid
+	case StarEvent:
+		return e.RemoteJid
+	case LabelEvent:
+		return e.Jid
+	case ReceiptEvent:
+		return e.RemoteJid
+	case CallEvent:
+		return e.From
+	}
+
+	if info, ok := eventInfo(event); ok {
+		return info.RemoteJid
+	}
+	return ""
+}
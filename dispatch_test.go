@@ -0,0 +1,107 @@
+package whatsapp
+
+import (
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestOrderedDispatcher(t *testing.T) {
+	d := newOrderedDispatcher(4, 100, func(err error) {
+		t.Errorf("unexpected error %v", err)
+	})
+
+	var mu sync.Mutex
+	seen := map[string][]int{}
+	wg := sync.WaitGroup{}
+	for i := 0; i < 20; i++ {
+		for _, chat := range []string{"a", "b", "c"} {
+			i, chat := i, chat
+			wg.Add(1)
+			d.submit(chat, nil, func() {
+				defer wg.Done()
+				mu.Lock()
+				seen[chat] = append(seen[chat], i)
+				mu.Unlock()
+			})
+		}
+	}
+	wg.Wait()
+
+	for chat, calls := range seen {
+		for i, n := range calls {
+			if i != n {
+				t.Fatalf("calls of chat %s out of order: %v", chat, calls)
+			}
+		}
+	}
+}
+
+func TestOrderedDispatcherDrop(t *testing.T) {
+	var errs []error
+	d := newOrderedDispatcher(1, 2, func(err error) {
+		errs = append(errs, err)
+	})
+
+	block := make(chan struct{})
+	d.submit("a", nil, func() { <-block })
+	d.submit("a", nil, func() {})
+	d.submit("b", TextMessage{}, func() {})
+	// drops are reported at most once per second
+	d.submit("c", TextMessage{}, func() {})
+	close(block)
+
+	if len(errs) != 2 || errs[0] != ErrDispatchBackpressure {
+		t.Fatalf("unexpected errors %v", errs)
+	}
+	if e, ok := errs[1].(*ErrEventDropped); !ok || e.Chat != "b" || e.Count != 1 {
+		t.Errorf("expected dropped event of chat b, got %v", errs[1])
+	}
+}
+
+func TestOrderedDispatcherStop(t *testing.T) {
+	d := newOrderedDispatcher(2, 10, func(err error) {
+		t.Errorf("unexpected error %v", err)
+	})
+	d.stop()
+
+	called := make(chan struct{})
+	d.submit("a", nil, func() { close(called) })
+	select {
+	case <-called:
+		t.Fatal("stopped dispatcher ran a call")
+	case <-time.After(20 * time.Millisecond):
+	}
+
+	d.start()
+	select {
+	case <-called:
+	case <-time.After(time.Second):
+		t.Fatal("queued call did not run after restarting")
+	}
+}
+
+type panickingErrorHandler struct{}
+
+func (panickingErrorHandler) HandleError(err error) { panic(err) }
+
+func TestReportDispatchErrorRecovers(t *testing.T) {
+	h := &textHandler{}
+	wac := &Conn{handler: []Handler{panickingErrorHandler{}, h}}
+	wac.reportDispatchError(ErrDispatchBackpressure)
+	if h.errors != 1 {
+		t.Errorf("expected the error to be reported once, got %d", h.errors)
+	}
+}
+
+func TestEventChat(t *testing.T) {
+	if chat := eventChat(TextMessage{Info: MessageInfo{RemoteJid: "x@s.whatsapp.net"}}); chat != "x@s.whatsapp.net" {
+		t.Errorf("unexpected chat %q", chat)
+	}
+	if chat := eventChat(GroupEvent{GroupJid: "g@g.us"}); chat != "g@g.us" {
+		t.Errorf("unexpected chat %q", chat)
+	}
+	if chat := eventChat("json"); chat != "" {
+		t.Errorf("unexpected chat %q", chat)
+	}
+}
@@ -2,6 +2,7 @@ package whatsapp
 
 import (
 	"fmt"
+	"net/http"
 
 	"github.com/pkg/errors"
 )
@@ -14,7 +15,7 @@ var (
 	ErrNotConnected              = errors.New("not connected")
 	ErrInvalidWsData             = errors.New("received invalid data")
 	ErrInvalidWsState            = errors.New("can't handle binary data when not logged in")
-	ErrConnectionTimeout         = errors.New("connection timed out")
+	ErrConnectionTimeout         = &TimeoutError{Op: "connection"}
 	ErrMissingMessageTag         = errors.New("no messageTag specified or to short")
 	ErrInvalidHmac               = errors.New("invalid hmac")
 	ErrInvalidServerResponse     = errors.New("invalid response received from server")
@@ -22,8 +23,69 @@ var (
 	ErrInvalidWebsocket          = errors.New("invalid websocket")
 	ErrMessageTypeNotImplemented = errors.New("message type not implemented")
 	ErrOptionsNotProvided        = errors.New("new conn options not provided")
+	ErrNoProfilePicture          = errors.New("no profile picture")
+	ErrDispatchBackpressure      = errors.New("dispatch queue is filling up, handlers are too slow")
+
+	// ErrTimeout matches every *TimeoutError with errors.Is.
+	ErrTimeout = errors.New("timed out")
+	// ErrUnauthorized matches a *ServerError with status 401 with errors.Is.
+	ErrUnauthorized = errors.New("unauthorized")
+	// ErrRateLimited matches a *ServerError with status 429 with errors.Is.
+	ErrRateLimited = errors.New("rate limited")
 )
 
+/*
+ServerError is returned if the servers answered a request with an error status. Op describes the request, e.g.
+"message sending". Use errors.Is with ErrUnauthorized, ErrRateLimited or ErrServerRespondedWith404 to check for
+common status codes.
+*/
+type ServerError struct {
+	Op     string
+	Status int
+}
+
+func (e *ServerError) Error() string {
+	return fmt.Sprintf("%s responded with %d", e.Op, e.Status)
+}
+
+func (e *ServerError) Is(target error) bool {
+	switch target {
+	case ErrUnauthorized:
+		return e.Status == http.StatusUnauthorized
+	case ErrRateLimited:
+		return e.Status == http.StatusTooManyRequests
+	case ErrServerRespondedWith404:
+		return e.Status == http.StatusNotFound
+	}
+	return false
+}
+
+// TimeoutError is returned if no response to a request was received in time. It matches ErrTimeout with errors.Is.
+type TimeoutError struct {
+	Op string
+}
+
+func (e *TimeoutError) Error() string {
+	return e.Op + " timed out"
+}
+
+func (e *TimeoutError) Is(target error) bool {
+	return target == ErrTimeout
+}
+
+// checkStatus returns a *ServerError if status is not 200.
+func checkStatus(op string, status int) error {
+	if status != http.StatusOK {
+		return &ServerError{Op: op, Status: status}
+	}
+	return nil
+}
+
+// jsonStatus returns the status code of a json response decoded into a map, 0 if it has none.
+func jsonStatus(resp map[string]interface{}) int {
+	return int(jsonInt(resp["status"]))
+}
+
 type ErrConnectionFailed struct {
 	Err error
 }
@@ -40,3 +102,38 @@ type ErrConnectionClosed struct {
 func (e *ErrConnectionClosed) Error() string {
 	return fmt.Sprintf("server closed connection,code: %d,text: %s", e.Code, e.Text)
 }
+
+// ErrBlocked is returned by Send if Options.RefuseBlocked is set and the recipient is blocked.
+type ErrBlocked struct {
+	Jid string
+}
+
+func (e *ErrBlocked) Error() string {
+	return fmt.Sprintf("recipient %s is blocked", e.Jid)
+}
+
+/*
+ErrEventDropped is passed to HandleError if events could not be dispatched because the dispatch queue was full. It is
+reported at most once per second, Count is the number of events dropped since the last report and Chat and Event
+describe the last one.
+*/
+type ErrEventDropped struct {
+	Chat  string
+	Event interface{}
+	Count int
+}
+
+func (e *ErrEventDropped) Error() string {
+	return fmt.Sprintf("dispatch queue full, dropped %d events, last %T of chat %s", e.Count, e.Event, e.Chat)
+}
+
+// ErrHandlerPanic is passed to HandleError if a handler panicked while handling an event.
+type ErrHandlerPanic struct {
+	Event interface{}
+	Value interface{}
+	Stack []byte
+}
+
+func (e *ErrHandlerPanic) Error() string {
+	return fmt.Sprintf("handler panicked handling %T: %v", e.Event, e.Value)
+}
@@ -0,0 +1,72 @@
+package whatsapp
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+)
+
+func TestServerErrorIs(t *testing.T) {
+	tests := []struct {
+		status int
+		target error
+		want   bool
+	}{
+		{401, ErrUnauthorized, true},
+		{429, ErrRateLimited, true},
+		{404, ErrServerRespondedWith404, true},
+		{500, ErrUnauthorized, false},
+		{401, ErrRateLimited, false},
+		{401, ErrTimeout, false},
+	}
+	for _, tt := range tests {
+		err := fmt.Errorf("wrapped: %w", &ServerError{Op: "test", Status: tt.status})
+		if got := errors.Is(err, tt.target); got != tt.want {
+			t.Errorf("errors.Is(%d, %v) = %v, want %v", tt.status, tt.target, got, tt.want)
+		}
+	}
+
+	var serverErr *ServerError
+	if !errors.As(checkStatus("message sending", 429), &serverErr) || serverErr.Status != 429 {
+		t.Errorf("expected *ServerError with status 429, got %v", serverErr)
+	}
+	if err := checkStatus("message sending", 200); err != nil {
+		t.Errorf("expected no error for status 200, got %v", err)
+	}
+}
+
+func TestResponseErrors(t *testing.T) {
+	wac := &Conn{msgTimeout: 10 * time.Millisecond}
+
+	ch := make(chan string, 1)
+	ch <- `{"status":401}`
+	err := wac.waitChatResponse(ch, "chat modification")
+	if !errors.Is(err, ErrUnauthorized) {
+		t.Errorf("expected ErrUnauthorized, got %v", err)
+	}
+	if err.Error() != "chat modification responded with 401" {
+		t.Errorf("unexpected message %q", err)
+	}
+
+	err = wac.waitChatResponse(make(chan string), "chat modification")
+	var timeoutErr *TimeoutError
+	if !errors.Is(err, ErrTimeout) || !errors.As(err, &timeoutErr) || timeoutErr.Op != "chat modification" {
+		t.Errorf("expected timeout of chat modification, got %v", err)
+	}
+	if !errors.Is(ErrConnectionTimeout, ErrTimeout) {
+		t.Error("expected ErrConnectionTimeout to match ErrTimeout")
+	}
+}
+
+func TestBinaryResponseErrors(t *testing.T) {
+	wac := &Conn{session: &Session{MacKey: make([]byte, 32)}}
+
+	if _, err := wac.decryptBinaryData([]byte(`{"status":404}`)); err != ErrServerRespondedWith404 {
+		t.Errorf("expected ErrServerRespondedWith404, got %v", err)
+	}
+	_, err := wac.decryptBinaryData([]byte(`{"status":429}`))
+	if !errors.Is(err, ErrRateLimited) || err.Error() != "query responded with 429" {
+		t.Errorf("expected rate limited query, got %v", err)
+	}
+}
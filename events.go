@@ -0,0 +1,148 @@
+package whatsapp
+
+import (
+	"context"
+	"sync"
+)
+
+/*
+Subscription is a registration of an event handler created by On, OnIf or Conn.OnMatch. Events are delivered until
+Unsubscribe is called.
+*/
+type Subscription struct {
+	bus *eventBus
+	id  uint64
+}
+
+// Unsubscribe stops the delivery of events to the handler of the subscription.
+func (s *Subscription) Unsubscribe() {
+	s.bus.remove(s.id)
+}
+
+// SubscribeOption configures a subscription.
+type SubscribeOption func(*subscriber)
+
+/*
+Synchronously makes the dispatcher wait for the handler to return before dispatching the next event, like handlers
+implementing SyncHandler.
+*/
+func Synchronously() SubscribeOption {
+	return func(s *subscriber) {
+		s.sync = true
+	}
+}
+
+type subscriber struct {
+	id    uint64
+	match func(event interface{}) bool
+	call  func(ctx context.Context, event interface{})
+	sync  bool
+}
+
+type eventBus struct {
+	sync.RWMutex
+	nextId      uint64
+	subscribers []*subscriber
+}
+
+func (b *eventBus) add(s *subscriber, opts []SubscribeOption) *Subscription {
+	for _, opt := range opts {
+		opt(s)
+	}
+
+	b.Lock()
+	defer b.Unlock()
+	b.nextId++
+	s.id = b.nextId
+	b.subscribers = append(b.subscribers, s)
+
+	return &Subscription{bus: b, id: s.id}
+}
+
+func (b *eventBus) remove(id uint64) {
+	b.Lock()
+	defer b.Unlock()
+	for i, s := range b.subscribers {
+		if s.id == id {
+			b.subscribers = append(b.subscribers[:i:i], b.subscribers[i+1:]...)
+			return
+		}
+	}
+}
+
+// matching returns the subscribers the event should be delivered to.
+func (b *eventBus) matching(event interface{}) []*subscriber {
+	defer b.RUnlock()
+	b.RLock()
+
+	var subs []*subscriber
+	for _, s := range b.subscribers {
+		if s.match(event) {
+			subs = append(subs, s)
+		}
+	}
+	return subs
+}
+
+/*
+On subscribes fn to all dispatched events of type T, e.g.
+
+	whatsapp.On(wac, func(ctx context.Context, m whatsapp.TextMessage) { ... })
+
+T may also be an interface type, in which case fn receives every event implementing it. Errors are delivered as
+events of type error.
+*/
+func On[T any](wac *Conn, fn func(ctx context.Context, event T), opts ...SubscribeOption) *Subscription {
+	return OnIf(wac, nil, fn, opts...)
+}
+
+// OnIf subscribes fn to all dispatched events of type T for which match returns true. A nil match matches every event.
+func OnIf[T any](wac *Conn, match func(event T) bool, fn func(ctx context.Context, event T), opts ...SubscribeOption) *Subscription {
+	return wac.events.add(&subscriber{
+		match: func(event interface{}) bool {
+			e, ok := event.(T)
+			return ok && (match == nil || match(e))
+		},
+		call: func(ctx context.Context, event interface{}) {
+			fn(ctx, event.(T))
+		},
+	}, opts)
+}
+
+// OnMatch subscribes fn to all dispatched events for which match returns true, regardless of their type.
+func (wac *Conn) OnMatch(match func(event interface{}) bool, fn func(ctx context.Context, event interface{}), opts ...SubscribeOption) *Subscription {
+	return wac.events.add(&subscriber{match: match, call: fn}, opts)
+}
+
+type connContextKey struct{}
+
+// ConnFromContext returns the connection that dispatched the event of a handler context.
+func ConnFromContext(ctx context.Context) *Conn {
+	wac, _ := ctx.Value(connContextKey{}).(*Conn)
+	return wac
+}
+
+func (wac *Conn) eventContext(origin EventOrigin) context.Context {
+	ctx := context.WithValue(context.Background(), connContextKey{}, wac)
+	return context.WithValue(ctx, originContextKey{}, origin)
+}
+
+// publish delivers the event to the matching subscribers of the event bus.
+func (wac *Conn) publish(ctx context.Context, event interface{}) {
+	subs := wac.events.matching(event)
+	if len(subs) == 0 {
+		return
+	}
+
+	for _, s := range subs {
+		call := s.call
+		invoke := func() {
+			wac.invoke(ctx, event, func(ctx context.Context) { call(ctx, event) })
+		}
+		if s.sync {
+			invoke()
+		} else {
+			wac.async(event, invoke)
+		}
+	}
+}
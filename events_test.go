@@ -0,0 +1,54 @@
+package whatsapp
+
+import (
+	"context"
+	"errors"
+	"testing"
+)
+
+type textHandler struct {
+	texts  []string
+	errors int
+}
+
+func (h *textHandler) HandleError(err error)                 { h.errors++ }
+func (h *textHandler) HandleTextMessage(message TextMessage) { h.texts = append(h.texts, message.Text) }
+func (h *textHandler) ShouldCallSynchronously() bool         { return true }
+
+func TestEventBus(t *testing.T) {
+	wac := &Conn{}
+
+	var texts, fromMe []string
+	var events int
+	sub := On(wac, func(ctx context.Context, m TextMessage) {
+		texts = append(texts, m.Text)
+	}, Synchronously())
+	OnIf(wac, func(m TextMessage) bool { return m.Info.FromMe }, func(ctx context.Context, m TextMessage) {
+		fromMe = append(fromMe, m.Text)
+	}, Synchronously())
+	wac.OnMatch(func(event interface{}) bool { return true }, func(ctx context.Context, event interface{}) {
+		events++
+	}, Synchronously())
+
+	h := &textHandler{}
+	wac.AddHandler(h)
+
+	wac.handle(TextMessage{Text: "a"})
+	wac.handle(TextMessage{Text: "b", Info: MessageInfo{FromMe: true}})
+	wac.handle(errors.New("c"))
+	sub.Unsubscribe()
+	wac.handle(TextMessage{Text: "d"})
+
+	if len(texts) != 2 || texts[0] != "a" || texts[1] != "b" {
+		t.Errorf("unexpected texts %v", texts)
+	}
+	if len(fromMe) != 1 || fromMe[0] != "b" {
+		t.Errorf("unexpected own texts %v", fromMe)
+	}
+	if events != 4 {
+		t.Errorf("expected 4 events, got %d", events)
+	}
+	if len(h.texts) != 3 || h.errors != 1 {
+		t.Errorf("unexpected handler calls %v, %d errors", h.texts, h.errors)
+	}
+}
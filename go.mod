@@ -8,4 +8,4 @@ require (
 	google.golang.org/protobuf v1.25.0
 )
 
-go 1.13
+go 1.18
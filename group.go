@@ -1,11 +1,46 @@
 package whatsapp
 
 import (
+	"crypto/rand"
+	"encoding/hex"
 	"encoding/json"
 	"fmt"
+	"strconv"
+	"strings"
 	"time"
+
+	"github.com/Rhymen/go-whatsapp/binary"
+)
+
+// Status codes that are reported per participant when adding or removing group participants.
+const (
+	GroupParticipantOK            = 200
+	GroupParticipantPrivacy       = 403
+	GroupParticipantNotFound      = 404
+	GroupParticipantAlreadyMember = 409
 )
 
+/*
+GroupParticipantResult contains the outcome of a participant change for a single participant. If Status is
+GroupParticipantPrivacy the participant does not allow being added to groups; InviteCode can then be used to send them
+a GroupInviteMessage instead.
+*/
+type GroupParticipantResult struct {
+	Jid              string
+	Status           int
+	InviteCode       string
+	InviteExpiration int64
+}
+
+/*
+GroupParticipantsResult is returned by AddGroupParticipants and RemoveGroupParticipants. Status is the overall status
+of the request, the status of each participant is reported in Participants.
+*/
+type GroupParticipantsResult struct {
+	Status       int
+	Participants []GroupParticipantResult
+}
+
 func (wac *Conn) GetGroupMetaData(jid string) (<-chan string, error) {
 	data := []interface{}{"query", "GroupMetadata", jid}
 	return wac.writeJson(data)
@@ -54,11 +89,11 @@ func (wac *Conn) GroupInviteLink(jid string) (string, error) {
 			return "", fmt.Errorf("error decoding response message: %v\n", err)
 		}
 	case <-time.After(wac.msgTimeout):
-		return "", fmt.Errorf("request timed out")
+		return "", &TimeoutError{Op: "invite code request"}
 	}
 
-	if int(response["status"].(float64)) != 200 {
-		return "", fmt.Errorf("request responded with %d", response["status"])
+	if err := checkStatus("invite code request", jsonStatus(response)); err != nil {
+		return "", err
 	}
 
 	return response["code"].(string), nil
@@ -79,12 +114,203 @@ func (wac *Conn) GroupAcceptInviteCode(code string) (jid string, err error) {
 			return "", fmt.Errorf("error decoding response message: %v\n", err)
 		}
 	case <-time.After(wac.msgTimeout):
-		return "", fmt.Errorf("request timed out")
+		return "", &TimeoutError{Op: "invite request"}
 	}
 
-	if int(response["status"].(float64)) != 200 {
-		return "", fmt.Errorf("request responded with %d", response["status"])
+	if err := checkStatus("invite request", jsonStatus(response)); err != nil {
+		return "", err
 	}
 
 	return response["gid"].(string), nil
 }
+
+// AcceptGroupInvite joins the group of a received GroupInviteMessage and returns the jid of the joined group.
+func (wac *Conn) AcceptGroupInvite(msg GroupInviteMessage) (jid string, err error) {
+	if msg.InviteCode == "" {
+		return "", fmt.Errorf("group invite message contains no invite code")
+	}
+	if msg.Expired() {
+		return "", fmt.Errorf("group invite expired at %v", time.Unix(msg.InviteExpiration, 0))
+	}
+	return wac.GroupAcceptInviteCode(msg.InviteCode)
+}
+
+// SetGroupDescription changes the description of the group. An empty description removes it.
+func (wac *Conn) SetGroupDescription(jid, description string) error {
+	prev, err := wac.groupDescriptionId(jid)
+	if err != nil {
+		return err
+	}
+
+	b := make([]byte, 10)
+	if _, err := rand.Read(b); err != nil {
+		return err
+	}
+
+	desc := binary.Node{
+		Description: "description",
+		Attributes: map[string]string{
+			"id": strings.ToUpper(hex.EncodeToString(b)),
+		},
+	}
+	if prev != "" {
+		desc.Attributes["prev"] = prev
+	}
+	if description == "" {
+		desc.Attributes["delete"] = "true"
+	} else {
+		desc.Content = []binary.Node{{
+			Description: "data",
+			Content:     []byte(description),
+		}}
+	}
+
+	ch, err := wac.setGroupContent("description", jid, "", []binary.Node{desc})
+	if err != nil {
+		return err
+	}
+	_, err = wac.waitGroupResponse(ch, "set group description")
+	return err
+}
+
+func (wac *Conn) groupDescriptionId(jid string) (string, error) {
+	ch, err := wac.GetGroupMetaData(jid)
+	if err != nil {
+		return "", err
+	}
+	resp, err := wac.waitGroupResponse(ch, "query group metadata")
+	if err != nil {
+		return "", err
+	}
+	id, _ := resp["descId"].(string)
+	return id, nil
+}
+
+// SetGroupAnnounce sets whether only admins are allowed to send messages to the group.
+func (wac *Conn) SetGroupAnnounce(jid string, announce bool) error {
+	return wac.setGroupProp(jid, "announcement", announce)
+}
+
+// SetGroupLocked sets whether only admins are allowed to edit the group info (subject, description, picture).
+func (wac *Conn) SetGroupLocked(jid string, locked bool) error {
+	return wac.setGroupProp(jid, "locked", locked)
+}
+
+func (wac *Conn) setGroupProp(jid, prop string, value bool) error {
+	n := binary.Node{
+		Description: prop,
+		Attributes: map[string]string{
+			"value": strconv.FormatBool(value),
+		},
+	}
+
+	ch, err := wac.setGroupContent("prop", jid, "", []binary.Node{n})
+	if err != nil {
+		return err
+	}
+	_, err = wac.waitGroupResponse(ch, "set group "+prop)
+	return err
+}
+
+/*
+RevokeGroupInviteLink invalidates the current invite link of the group. The new invite code is returned if the server
+provides it, otherwise it can be queried with GroupInviteLink.
+*/
+func (wac *Conn) RevokeGroupInviteLink(jid string) (string, error) {
+	ch, err := wac.setGroupContent("revoke_invite", jid, "", nil)
+	if err != nil {
+		return "", err
+	}
+	resp, err := wac.waitGroupResponse(ch, "revoke group invite")
+	if err != nil {
+		return "", err
+	}
+	code, _ := resp["code"].(string)
+	return code, nil
+}
+
+/*
+SetGroupPicture changes the group picture. JPEG and PNG images of any size are accepted, they are cropped to a centered
+square and scaled to 640x640 (image) and 96x96 (preview) JPEGs. If preview is nil, it is created from image.
+*/
+func (wac *Conn) SetGroupPicture(jid string, image, preview []byte) error {
+	ch, err := wac.uploadPicture(jid, image, preview, pic)
+	if err != nil {
+		return err
+	}
+	_, err = wac.waitGroupResponse(ch, "set group picture")
+	return err
+}
+
+// AddGroupParticipants adds participants to the group and reports the result for every participant.
+func (wac *Conn) AddGroupParticipants(jid string, participants []string) (*GroupParticipantsResult, error) {
+	return wac.changeGroupParticipants("add", jid, participants)
+}
+
+// RemoveGroupParticipants removes participants from the group and reports the result for every participant.
+func (wac *Conn) RemoveGroupParticipants(jid string, participants []string) (*GroupParticipantsResult, error) {
+	return wac.changeGroupParticipants("remove", jid, participants)
+}
+
+func (wac *Conn) changeGroupParticipants(t, jid string, participants []string) (*GroupParticipantsResult, error) {
+	ch, err := wac.setGroup(t, jid, "", participants)
+	if err != nil {
+		return nil, err
+	}
+
+	resp, err := wac.waitGroupResponse(ch, t+" group participants")
+	if err != nil {
+		return nil, err
+	}
+
+	return parseGroupParticipantsResult(resp), nil
+}
+
+func parseGroupParticipantsResult(resp map[string]interface{}) *GroupParticipantsResult {
+	result := &GroupParticipantsResult{
+		Status: jsonStatus(resp),
+	}
+
+	list, _ := resp["participants"].([]interface{})
+	for _, entry := range list {
+		m, ok := entry.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		for participant, v := range m {
+			attrs, _ := v.(map[string]interface{})
+			r := GroupParticipantResult{
+				Jid:    strings.Replace(participant, "@c.us", "@s.whatsapp.net", 1),
+				Status: int(jsonInt(attrs["code"])),
+			}
+			if r.Status == 0 {
+				r.Status = result.Status
+			}
+			r.InviteCode, _ = attrs["invite_code"].(string)
+			r.InviteExpiration = jsonInt(attrs["invite_code_exp"])
+			result.Participants = append(result.Participants, r)
+		}
+	}
+
+	return result
+}
+
+// waitGroupResponse waits for the json response of a group request. Responses with a status code >= 400 are errors.
+func (wac *Conn) waitGroupResponse(ch <-chan string, op string) (map[string]interface{}, error) {
+	var response map[string]interface{}
+
+	select {
+	case r := <-ch:
+		if err := json.Unmarshal([]byte(r), &response); err != nil {
+			return nil, fmt.Errorf("error decoding %s response: %v", op, err)
+		}
+	case <-time.After(wac.msgTimeout):
+		return nil, &TimeoutError{Op: op}
+	}
+
+	if status := jsonStatus(response); status >= 400 {
+		return response, &ServerError{Op: op, Status: status}
+	}
+
+	return response, nil
+}
@@ -0,0 +1,239 @@
+package whatsapp
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/Rhymen/go-whatsapp/binary/proto"
+)
+
+const defaultGroupMetadataTTL = 10 * time.Minute
+
+/*
+GroupMetadata contains the information about a group. It is returned by GroupInfo and cached in the Store, where it is
+kept up to date by the GroupEvents received from the server.
+*/
+type GroupMetadata struct {
+	Jid              string
+	Owner            string
+	Subject          string
+	SubjectOwner     string
+	SubjectTime      time.Time
+	Creation         time.Time
+	Description      string
+	DescriptionId    string
+	DescriptionOwner string
+	DescriptionTime  time.Time
+	Participants     []GroupParticipant
+
+	// Announce is set if only admins are allowed to send messages.
+	Announce bool
+	// Locked is set if only admins are allowed to edit the group info.
+	Locked bool
+
+	fetched time.Time
+}
+
+// GroupParticipant is a member of a group.
+type GroupParticipant struct {
+	Jid          string
+	IsAdmin      bool
+	IsSuperAdmin bool
+}
+
+// Participant returns the participant with the given jid.
+func (g GroupMetadata) Participant(jid string) (GroupParticipant, bool) {
+	for _, p := range g.Participants {
+		if p.Jid == jid {
+			return p, true
+		}
+	}
+	return GroupParticipant{}, false
+}
+
+type groupMetadataResponse struct {
+	Status       int        `json:"status"`
+	Id           string     `json:"id"`
+	Owner        string     `json:"owner"`
+	Subject      string     `json:"subject"`
+	SubjectOwner string     `json:"subjectOwner"`
+	SubjectTime  int64      `json:"subjectTime"`
+	Creation     int64      `json:"creation"`
+	Desc         string     `json:"desc"`
+	DescId       string     `json:"descId"`
+	DescOwner    string     `json:"descOwner"`
+	DescTime     int64      `json:"descTime"`
+	Announce     stringBool `json:"announce"`
+	Restrict     stringBool `json:"restrict"`
+	Participants []struct {
+		Id           string `json:"id"`
+		IsAdmin      bool   `json:"isAdmin"`
+		IsSuperAdmin bool   `json:"isSuperAdmin"`
+	} `json:"participants"`
+}
+
+// stringBool decodes booleans that are sent either as json booleans or as "true"/"false" strings.
+type stringBool bool
+
+func (b *stringBool) UnmarshalJSON(data []byte) error {
+	s := strings.Trim(string(data), `"`)
+	*b = stringBool(s == "true" || s == "on")
+	return nil
+}
+
+func unixTime(ts int64) time.Time {
+	if ts == 0 {
+		return time.Time{}
+	}
+	return time.Unix(ts, 0)
+}
+
+func (r *groupMetadataResponse) metadata() GroupMetadata {
+	g := GroupMetadata{
+		Jid:              r.Id,
+		Owner:            toSWhatsAppJid(r.Owner),
+		Subject:          r.Subject,
+		SubjectOwner:     toSWhatsAppJid(r.SubjectOwner),
+		SubjectTime:      unixTime(r.SubjectTime),
+		Creation:         unixTime(r.Creation),
+		Description:      r.Desc,
+		DescriptionId:    r.DescId,
+		DescriptionOwner: toSWhatsAppJid(r.DescOwner),
+		DescriptionTime:  unixTime(r.DescTime),
+		Announce:         bool(r.Announce),
+		Locked:           bool(r.Restrict),
+		Participants:     make([]GroupParticipant, 0, len(r.Participants)),
+	}
+	for _, p := range r.Participants {
+		g.Participants = append(g.Participants, GroupParticipant{
+			Jid:          toSWhatsAppJid(p.Id),
+			IsAdmin:      p.IsAdmin || p.IsSuperAdmin,
+			IsSuperAdmin: p.IsSuperAdmin,
+		})
+	}
+	return g
+}
+
+func toSWhatsAppJid(jid string) string {
+	return strings.Replace(jid, "@c.us", "@s.whatsapp.net", 1)
+}
+
+/*
+GroupInfo returns the metadata of the group. The metadata is cached in the Store and only requested from the server
+if there is no cached entry or it is older than Options.GroupMetadataTTL. Changes to the group received from the
+server update the cached entry, membership changes invalidate it.
+*/
+func (wac *Conn) GroupInfo(jid string) (*GroupMetadata, error) {
+	if g, ok := wac.Store.GetGroupMetadata(jid); ok && time.Since(g.fetched) < wac.groupMetadataTTL {
+		return &g, nil
+	}
+
+	ch, err := wac.GetGroupMetaData(jid)
+	if err != nil {
+		return nil, err
+	}
+
+	var resp groupMetadataResponse
+	select {
+	case r := <-ch:
+		if err := json.Unmarshal([]byte(r), &resp); err != nil {
+			return nil, fmt.Errorf("error decoding group metadata: %v", err)
+		}
+	case <-time.After(wac.msgTimeout):
+		return nil, &TimeoutError{Op: "group metadata request"}
+	}
+
+	if resp.Status != 0 && resp.Status != 200 {
+		return nil, &ServerError{Op: "group metadata request", Status: resp.Status}
+	}
+
+	g := resp.metadata()
+	if g.Jid == "" {
+		g.Jid = jid
+	}
+	g.fetched = time.Now()
+	wac.Store.setGroupMetadata(g)
+
+	return &g, nil
+}
+
+// GroupAction describes the kind of change of a GroupEvent.
+type GroupAction string
+
+const (
+	GroupActionCreate      GroupAction = "create"
+	GroupActionSubject     GroupAction = "subject"
+	GroupActionDescription GroupAction = "description"
+	GroupActionPicture     GroupAction = "picture"
+	GroupActionInviteLink  GroupAction = "invite_link"
+	GroupActionAnnounce    GroupAction = "announcement"
+	GroupActionLocked      GroupAction = "locked"
+	GroupActionAdd         GroupAction = "add"
+	GroupActionRemove      GroupAction = "remove"
+	GroupActionPromote     GroupAction = "promote"
+	GroupActionDemote      GroupAction = "demote"
+	GroupActionJoin        GroupAction = "join"
+	GroupActionLeave       GroupAction = "leave"
+	GroupActionDelete      GroupAction = "delete"
+)
+
+var groupStubActions = map[proto.WebMessageInfo_WebMessageInfoStubType]GroupAction{
+	proto.WebMessageInfo_GROUP_CREATE:              GroupActionCreate,
+	proto.WebMessageInfo_GROUP_CHANGE_SUBJECT:      GroupActionSubject,
+	proto.WebMessageInfo_GROUP_CHANGE_DESCRIPTION:  GroupActionDescription,
+	proto.WebMessageInfo_GROUP_CHANGE_ICON:         GroupActionPicture,
+	proto.WebMessageInfo_GROUP_CHANGE_INVITE_LINK:  GroupActionInviteLink,
+	proto.WebMessageInfo_GROUP_CHANGE_ANNOUNCE:     GroupActionAnnounce,
+	proto.WebMessageInfo_GROUP_CHANGE_RESTRICT:     GroupActionLocked,
+	proto.WebMessageInfo_GROUP_PARTICIPANT_ADD:     GroupActionAdd,
+	proto.WebMessageInfo_GROUP_PARTICIPANT_REMOVE:  GroupActionRemove,
+	proto.WebMessageInfo_GROUP_PARTICIPANT_PROMOTE: GroupActionPromote,
+	proto.WebMessageInfo_GROUP_PARTICIPANT_DEMOTE:  GroupActionDemote,
+	proto.WebMessageInfo_GROUP_PARTICIPANT_INVITE:  GroupActionJoin,
+	proto.WebMessageInfo_GROUP_PARTICIPANT_LEAVE:   GroupActionLeave,
+	proto.WebMessageInfo_GROUP_DELETE:              GroupActionDelete,
+}
+
+/*
+GroupEvent represents a change of a group, e.g. a new subject or added participants. Author is the participant that
+made the change. Participants contains the affected participants for membership and admin changes, Value the new
+subject or description, or "on"/"off" for the announcement and locked settings.
+*/
+type GroupEvent struct {
+	Info         MessageInfo
+	GroupJid     string
+	Author       string
+	Action       GroupAction
+	Participants []string
+	Value        string
+}
+
+func isGroupEvent(msg *proto.WebMessageInfo) bool {
+	_, ok := groupStubActions[msg.GetMessageStubType()]
+	return ok
+}
+
+func getGroupEvent(msg *proto.WebMessageInfo) GroupEvent {
+	event := GroupEvent{
+		Info:     getMessageInfo(msg),
+		GroupJid: msg.GetKey().GetRemoteJid(),
+		Author:   toSWhatsAppJid(msg.GetParticipant()),
+		Action:   groupStubActions[msg.GetMessageStubType()],
+	}
+
+	params := msg.GetMessageStubParameters()
+	switch event.Action {
+	case GroupActionAdd, GroupActionRemove, GroupActionPromote, GroupActionDemote, GroupActionJoin, GroupActionLeave:
+		for _, p := range params {
+			event.Participants = append(event.Participants, toSWhatsAppJid(p))
+		}
+	default:
+		if len(params) > 0 {
+			event.Value = params[0]
+		}
+	}
+
+	return event
+}
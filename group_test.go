@@ -0,0 +1,27 @@
+package whatsapp
+
+import (
+	"encoding/json"
+	"testing"
+)
+
+func TestParseGroupParticipantsResult(t *testing.T) {
+	var resp map[string]interface{}
+	err := json.Unmarshal([]byte(`{"status":207,"participants":[{"4912345@c.us":{"code":"200"}},{"4967890@c.us":{"code":403,"invite_code":"AbCd","invite_code_exp":"1600000000"}}]}`), &resp)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	result := parseGroupParticipantsResult(resp)
+	if result.Status != 207 || len(result.Participants) != 2 {
+		t.Fatalf("unexpected result %+v", result)
+	}
+
+	ok, privacy := result.Participants[0], result.Participants[1]
+	if ok.Jid != "4912345@s.whatsapp.net" || ok.Status != GroupParticipantOK {
+		t.Errorf("unexpected participant %+v", ok)
+	}
+	if privacy.Status != GroupParticipantPrivacy || privacy.InviteCode != "AbCd" || privacy.InviteExpiration != 1600000000 {
+		t.Errorf("unexpected participant %+v", privacy)
+	}
+}
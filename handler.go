@@ -1,8 +1,8 @@
 package whatsapp
 
 import (
+	"context"
 	"fmt"
-	"os"
 	"strings"
 
 	"github.com/Rhymen/go-whatsapp/binary"
@@ -113,6 +113,78 @@ type OrderMessageHandler interface {
 	HandleOrderMessage(message OrderMessage)
 }
 
+/*
+The GroupInviteMessageHandler interface needs to be implemented to receive group invite messages dispatched by the dispatcher.
+*/
+type GroupInviteMessageHandler interface {
+	Handler
+	HandleGroupInviteMessage(message GroupInviteMessage)
+}
+
+/*
+The GroupEventHandler interface needs to be implemented to receive group changes dispatched by the dispatcher.
+*/
+type GroupEventHandler interface {
+	Handler
+	HandleGroupEvent(event GroupEvent)
+}
+
+/*
+The StarEventHandler interface needs to be implemented to receive star changes dispatched by the dispatcher.
+*/
+type StarEventHandler interface {
+	Handler
+	HandleStarEvent(event StarEvent)
+}
+
+/*
+The LabelEventHandler interface needs to be implemented to receive label changes dispatched by the dispatcher.
+*/
+type LabelEventHandler interface {
+	Handler
+	HandleLabelEvent(event LabelEvent)
+}
+
+/*
+The QuickReplyEventHandler interface needs to be implemented to receive quick reply changes dispatched by the dispatcher.
+*/
+type QuickReplyEventHandler interface {
+	Handler
+	HandleQuickReplyEvent(event QuickReplyEvent)
+}
+
+/*
+The CallHandler interface needs to be implemented to receive call events dispatched by the dispatcher.
+*/
+type CallHandler interface {
+	Handler
+	HandleCallEvent(event CallEvent)
+}
+
+/*
+The BlockListHandler interface needs to be implemented to receive block list changes dispatched by the dispatcher.
+*/
+type BlockListHandler interface {
+	Handler
+	HandleBlockListChanged(event BlockListChangedEvent)
+}
+
+/*
+The StatusUpdateHandler interface needs to be implemented to receive status updates of contacts dispatched by the dispatcher.
+*/
+type StatusUpdateHandler interface {
+	Handler
+	HandleStatusUpdate(event StatusUpdateEvent)
+}
+
+/*
+The ReceiptHandler interface needs to be implemented to receive message receipts dispatched by the dispatcher.
+*/
+type ReceiptHandler interface {
+	Handler
+	HandleReceipt(receipt ReceiptEvent)
+}
+
 /*
 The JsonMessageHandler interface needs to be implemented to receive json messages dispatched by the dispatcher.
 These json messages contain status updates of every kind sent by WhatsAppWeb servers. WhatsAppWeb uses these messages
@@ -201,179 +273,103 @@ func (wac *Conn) shouldCallSynchronously(handler Handler) bool {
 	return ok && sh.ShouldCallSynchronously()
 }
 
-func (wac *Conn) handle(message interface{}) {
-	wac.handleWithCustomHandlers(message, wac.handler)
-}
+/*
+A handlerAdapter delivers events to the handlers implementing the matching handler interface. It returns a function
+calling the handler, or false if the handler does not handle the event.
+*/
+type handlerAdapter func(h Handler, event interface{}) (func(), bool)
 
-func (wac *Conn) handleWithCustomHandlers(message interface{}, handlers []Handler) {
-	switch m := message.(type) {
-	case error:
-		for _, h := range handlers {
-			if wac.shouldCallSynchronously(h) {
-				h.HandleError(m)
-			} else {
-				go h.HandleError(m)
-			}
-		}
-	case string:
-		for _, h := range handlers {
-			if x, ok := h.(JsonMessageHandler); ok {
-				if wac.shouldCallSynchronously(h) {
-					x.HandleJsonMessage(m)
-				} else {
-					go x.HandleJsonMessage(m)
-				}
-			}
-		}
-	case TextMessage:
-		for _, h := range handlers {
-			if x, ok := h.(TextMessageHandler); ok {
-				if wac.shouldCallSynchronously(h) {
-					x.HandleTextMessage(m)
-				} else {
-					go x.HandleTextMessage(m)
-				}
-			}
-		}
-	case ImageMessage:
-		for _, h := range handlers {
-			if x, ok := h.(ImageMessageHandler); ok {
-				if wac.shouldCallSynchronously(h) {
-					x.HandleImageMessage(m)
-				} else {
-					go x.HandleImageMessage(m)
-				}
-			}
-		}
-	case VideoMessage:
-		for _, h := range handlers {
-			if x, ok := h.(VideoMessageHandler); ok {
-				if wac.shouldCallSynchronously(h) {
-					x.HandleVideoMessage(m)
-				} else {
-					go x.HandleVideoMessage(m)
-				}
-			}
-		}
-	case AudioMessage:
-		for _, h := range handlers {
-			if x, ok := h.(AudioMessageHandler); ok {
-				if wac.shouldCallSynchronously(h) {
-					x.HandleAudioMessage(m)
-				} else {
-					go x.HandleAudioMessage(m)
-				}
-			}
-		}
-	case DocumentMessage:
-		for _, h := range handlers {
-			if x, ok := h.(DocumentMessageHandler); ok {
-				if wac.shouldCallSynchronously(h) {
-					x.HandleDocumentMessage(m)
-				} else {
-					go x.HandleDocumentMessage(m)
-				}
-			}
-		}
-	case LocationMessage:
-		for _, h := range handlers {
-			if x, ok := h.(LocationMessageHandler); ok {
-				if wac.shouldCallSynchronously(h) {
-					x.HandleLocationMessage(m)
-				} else {
-					go x.HandleLocationMessage(m)
-				}
-			}
+// adapt creates a handlerAdapter from a method expression of a handler interface, e.g. TextMessageHandler.HandleTextMessage.
+func adapt[H any, T any](method func(H, T)) handlerAdapter {
+	return func(h Handler, event interface{}) (func(), bool) {
+		e, ok := event.(T)
+		if !ok {
+			return nil, false
 		}
-	case LiveLocationMessage:
-		for _, h := range handlers {
-			if x, ok := h.(LiveLocationMessageHandler); ok {
-				if wac.shouldCallSynchronously(h) {
-					x.HandleLiveLocationMessage(m)
-				} else {
-					go x.HandleLiveLocationMessage(m)
-				}
-			}
+		x, ok := h.(H)
+		if !ok {
+			return nil, false
 		}
+		return func() { method(x, e) }, true
+	}
+}
 
-	case StickerMessage:
-		for _, h := range handlers {
-			if x, ok := h.(StickerMessageHandler); ok {
-				if wac.shouldCallSynchronously(h) {
-					x.HandleStickerMessage(m)
-				} else {
-					go x.HandleStickerMessage(m)
-				}
-			}
-		}
+/*
+handlerAdapters connects the handler interfaces to the dispatcher. New event types only need a handler interface and an
+entry here, or can be received with On without any handler interface.
+*/
+var handlerAdapters = []handlerAdapter{
+	adapt(Handler.HandleError),
+	adapt(JsonMessageHandler.HandleJsonMessage),
+	adapt(TextMessageHandler.HandleTextMessage),
+	adapt(ImageMessageHandler.HandleImageMessage),
+	adapt(VideoMessageHandler.HandleVideoMessage),
+	adapt(AudioMessageHandler.HandleAudioMessage),
+	adapt(DocumentMessageHandler.HandleDocumentMessage),
+	adapt(LocationMessageHandler.HandleLocationMessage),
+	adapt(LiveLocationMessageHandler.HandleLiveLocationMessage),
+	adapt(StickerMessageHandler.HandleStickerMessage),
+	adapt(ContactMessageHandler.HandleContactMessage),
+	adapt(BatteryMessageHandler.HandleBatteryMessage),
+	adapt(NewContactHandler.HandleNewContact),
+	adapt(ProductMessageHandler.HandleProductMessage),
+	adapt(OrderMessageHandler.HandleOrderMessage),
+	adapt(GroupInviteMessageHandler.HandleGroupInviteMessage),
+	adapt(GroupEventHandler.HandleGroupEvent),
+	adapt(StarEventHandler.HandleStarEvent),
+	adapt(LabelEventHandler.HandleLabelEvent),
+	adapt(QuickReplyEventHandler.HandleQuickReplyEvent),
+	adapt(CallHandler.HandleCallEvent),
+	adapt(BlockListHandler.HandleBlockListChanged),
+	adapt(StatusUpdateHandler.HandleStatusUpdate),
+	adapt(ReceiptHandler.HandleReceipt),
+	adapt(RawMessageHandler.HandleRawMessage),
+	adapt(ContactListHandler.HandleContactList),
+	adapt(ChatListHandler.HandleChatList),
+}
 
-	case ContactMessage:
-		for _, h := range handlers {
-			if x, ok := h.(ContactMessageHandler); ok {
-				if wac.shouldCallSynchronously(h) {
-					x.HandleContactMessage(m)
-				} else {
-					go x.HandleContactMessage(m)
-				}
-			}
-		}
+// handle dispatches the message to the registered handlers and the subscribers of the event bus.
+func (wac *Conn) handle(message interface{}) {
+	wac.handleWithOrigin(message, OriginLive)
+}
 
-	case BatteryMessage:
-		for _, h := range handlers {
-			if x, ok := h.(BatteryMessageHandler); ok {
-				if wac.shouldCallSynchronously(h) {
-					x.HandleBatteryMessage(m)
-				} else {
-					go x.HandleBatteryMessage(m)
-				}
-			}
-		}
+func (wac *Conn) handleWithOrigin(message interface{}, origin EventOrigin) {
+	message = wac.withConn(withOrigin(message, origin))
+	ctx := wac.eventContext(origin)
+	wac.notifyHandlers(ctx, message, wac.handler)
+	wac.publish(ctx, message)
+}
 
-	case Contact:
-		for _, h := range handlers {
-			if x, ok := h.(NewContactHandler); ok {
-				if wac.shouldCallSynchronously(h) {
-					x.HandleNewContact(m)
-				} else {
-					go x.HandleNewContact(m)
-				}
-			}
-		}
+/*
+handleWithCustomHandlers dispatches messages loaded from the chat history to the given handlers only. If handlers is
+nil, the registered handlers and subscribers are used.
+*/
+func (wac *Conn) handleWithCustomHandlers(message interface{}, handlers []Handler) {
+	if handlers == nil {
+		wac.handleWithOrigin(message, OriginHistory)
+		return
+	}
+	message = wac.withConn(withOrigin(message, OriginHistory))
+	wac.notifyHandlers(wac.eventContext(OriginHistory), message, handlers)
+}
 
-	case ProductMessage:
-		for _, h := range handlers {
-			if x, ok := h.(ProductMessageHandler); ok {
-				if wac.shouldCallSynchronously(h) {
-					x.HandleProductMessage(m)
-				} else {
-					go x.HandleProductMessage(m)
-				}
+func (wac *Conn) notifyHandlers(ctx context.Context, message interface{}, handlers []Handler) {
+	for _, h := range handlers {
+		for _, adapter := range handlerAdapters {
+			call, ok := adapter(h, message)
+			if !ok {
+				continue
 			}
-		}
-
-	case OrderMessage:
-		for _, h := range handlers {
-			if x, ok := h.(OrderMessageHandler); ok {
-				if wac.shouldCallSynchronously(h) {
-					x.HandleOrderMessage(m)
-				} else {
-					go x.HandleOrderMessage(m)
-				}
+			invoke := func() {
+				wac.invoke(ctx, message, func(context.Context) { call() })
 			}
-		}
-
-	case *proto.WebMessageInfo:
-		for _, h := range handlers {
-			if x, ok := h.(RawMessageHandler); ok {
-				if wac.shouldCallSynchronously(h) {
-					x.HandleRawMessage(m)
-				} else {
-					go x.HandleRawMessage(m)
-				}
+			if wac.shouldCallSynchronously(h) {
+				invoke()
+			} else {
+				wac.async(message, invoke)
 			}
 		}
 	}
-
 }
 
 func (wac *Conn) handleContacts(contacts interface{}) {
@@ -396,15 +392,7 @@ func (wac *Conn) handleContacts(contacts interface{}) {
 			contactNode.Attributes["short"],
 		})
 	}
-	for _, h := range wac.handler {
-		if x, ok := h.(ContactListHandler); ok {
-			if wac.shouldCallSynchronously(h) {
-				x.HandleContactList(contactList)
-			} else {
-				go x.HandleContactList(contactList)
-			}
-		}
-	}
+	wac.handle(contactList)
 }
 
 func (wac *Conn) handleChats(chats interface{}) {
@@ -427,17 +415,24 @@ func (wac *Conn) handleChats(chats interface{}) {
 			chatNode.Attributes["t"],
 			chatNode.Attributes["mute"],
 			chatNode.Attributes["spam"],
+			chatNode.Attributes["archive"],
+			chatNode.Attributes["pin"],
 		})
 	}
-	for _, h := range wac.handler {
-		if x, ok := h.(ChatListHandler); ok {
-			if wac.shouldCallSynchronously(h) {
-				x.HandleChatList(chatList)
-			} else {
-				go x.HandleChatList(chatList)
-			}
-		}
+	wac.handle(chatList)
+}
+
+// handleParsed applies live updates of a parsed message to the Store before handing it to the handlers.
+func (wac *Conn) handleParsed(msg interface{}, origin EventOrigin) {
+	switch m := msg.(type) {
+	case GroupEvent:
+		wac.Store.updateGroupMetadata(m)
+	case LabelEvent:
+		wac.Store.updateLabels(m)
+	case QuickReplyEvent:
+		wac.Store.updateQuickReply(m)
 	}
+	wac.handleWithOrigin(msg, origin)
 }
 
 func (wac *Conn) dispatch(msg interface{}) {
@@ -448,22 +443,29 @@ func (wac *Conn) dispatch(msg interface{}) {
 	switch message := msg.(type) {
 	case *binary.Node:
 		if message.Description == "action" {
+			origin := actionOrigin(message.Attributes)
 			if con, ok := message.Content.([]interface{}); ok {
 				for a := range con {
 					if v, ok := con[a].(*proto.WebMessageInfo); ok {
-						wac.handle(v)
-						wac.handle(ParseProtoMessage(v))
+						wac.handleWithOrigin(v, origin)
+						parsed := ParseProtoMessage(v)
+						wac.handleParsed(parsed, origin)
+						if _, isErr := parsed.(error); !isErr && v.GetKey().GetRemoteJid() == StatusBroadcastJid {
+							wac.handleWithOrigin(getStatusUpdateEvent(v, parsed), origin)
+						}
 					}
 
 					if v, ok := con[a].(binary.Node); ok {
-						wac.handle(ParseNodeMessage(v))
+						wac.handleParsed(ParseNodeMessage(v), origin)
 					}
 				}
 			} else if con, ok := message.Content.([]binary.Node); ok {
 				for a := range con {
-					wac.handle(ParseNodeMessage(con[a]))
+					wac.handleParsed(ParseNodeMessage(con[a]), origin)
 				}
 			}
+		} else if message.Description == "call" {
+			wac.handleParsed(ParseNodeMessage(*message), OriginLive)
 		} else if message.Description == "response" && message.Attributes["type"] == "contacts" {
 			wac.Store.updateContacts(message.Content)
 			wac.handleContacts(message.Content)
@@ -472,10 +474,18 @@ func (wac *Conn) dispatch(msg interface{}) {
 			wac.handleChats(message.Content)
 		}
 	case error:
+		wac.log(LogWarn, "dispatching error", "err", message)
 		wac.handle(message)
 	case string:
+		if jids, ok := parseBlockList(message); ok {
+			added, removed := wac.Store.setBlockList(jids)
+			wac.handle(BlockListChangedEvent{Jids: jids, Added: added, Removed: removed})
+		} else if receipt, ok := parseReceipt(message); ok {
+			wac.broadcasts.update(receipt)
+			wac.handle(receipt)
+		}
 		wac.handle(message)
 	default:
-		fmt.Fprintf(os.Stderr, "unknown type in dipatcher chan: %T", msg)
+		wac.log(LogWarn, "unknown type in dispatcher", "type", fmt.Sprintf("%T", msg))
 	}
 }
@@ -0,0 +1,44 @@
+package whatsapp
+
+import (
+	"bytes"
+	"encoding/json"
+	"strconv"
+)
+
+// jsonNumber decodes integers that are sent either as json numbers or as strings.
+type jsonNumber int64
+
+func (n *jsonNumber) UnmarshalJSON(data []byte) error {
+	d := json.NewDecoder(bytes.NewReader(data))
+	d.UseNumber()
+	var v interface{}
+	if err := d.Decode(&v); err != nil {
+		return err
+	}
+	*n = jsonNumber(jsonInt(v))
+	return nil
+}
+
+/*
+jsonInt converts a decoded json value that the server sends either as number or as string, e.g. status codes, to an
+integer. Values that are no number are 0.
+*/
+func jsonInt(v interface{}) int64 {
+	var s string
+	switch c := v.(type) {
+	case float64:
+		return int64(c)
+	case json.Number:
+		s = string(c)
+	case string:
+		s = c
+	default:
+		return 0
+	}
+	if i, err := strconv.ParseInt(s, 10, 64); err == nil {
+		return i
+	}
+	f, _ := strconv.ParseFloat(s, 64)
+	return int64(f)
+}
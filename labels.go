@@ -0,0 +1,228 @@
+package whatsapp
+
+import (
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/Rhymen/go-whatsapp/binary"
+)
+
+/*
+Label is a WhatsApp Business label. Color is the index of the label color in the palette of the WhatsApp apps.
+*/
+type Label struct {
+	Id    string
+	Name  string
+	Color int
+}
+
+// LabelAction describes the kind of change of a LabelEvent.
+type LabelAction string
+
+const (
+	LabelAdded      LabelAction = "add"
+	LabelEdited     LabelAction = "modify"
+	LabelDeleted    LabelAction = "delete"
+	LabelAssigned   LabelAction = "assign"
+	LabelUnassigned LabelAction = "unassign"
+)
+
+/*
+LabelEvent is dispatched when labels are changed, e.g. on the phone. For LabelAssigned and LabelUnassigned Jid is the
+chat the label was (un)assigned to and Key is set if the label was (un)assigned to a single message of that chat.
+*/
+type LabelEvent struct {
+	Action LabelAction
+	Label  Label
+	Jid    string
+	Key    *MessageKey
+}
+
+func getLabel(attributes map[string]string) Label {
+	color, _ := strconv.Atoi(attributes["color"])
+	return Label{
+		Id:    attributes["id"],
+		Name:  attributes["name"],
+		Color: color,
+	}
+}
+
+func getLabelEvent(msg binary.Node) LabelEvent {
+	action := LabelAction(msg.Attributes["type"])
+	if action != LabelEdited && action != LabelDeleted {
+		action = LabelAdded
+	}
+	return LabelEvent{
+		Action: action,
+		Label:  getLabel(msg.Attributes),
+	}
+}
+
+func getChatLabelEvent(msg binary.Node) interface{} {
+	children := nodeChildren(msg.Content)
+	if len(children) == 0 {
+		return nil
+	}
+	label := children[0]
+
+	event := LabelEvent{
+		Action: LabelAssigned,
+		Label:  getLabel(label.Attributes),
+		Jid:    toSWhatsAppJid(msg.Attributes["jid"]),
+	}
+	if label.Attributes["type"] == "remove" {
+		event.Action = LabelUnassigned
+	}
+	if index := label.Attributes["index"]; index != "" {
+		event.Key = &MessageKey{
+			Id:     index,
+			FromMe: label.Attributes["owner"] == "true",
+		}
+	}
+
+	return event
+}
+
+// Labels queries the labels of the account and stores them in the Store.
+func (wac *Conn) Labels() ([]Label, error) {
+	node, err := wac.query("label", "", "", "", "", "", 0, 0)
+	if err != nil {
+		return nil, err
+	}
+
+	var labels []Label
+	for _, n := range nodeChildren(node.Content) {
+		if n.Description == "label" {
+			labels = append(labels, getLabel(n.Attributes))
+		}
+	}
+
+	wac.Store.setLabels(labels)
+	return labels, nil
+}
+
+/*
+CreateLabel creates a new label. The id of the new label is chosen based on the labels in the Store, so Labels should
+have been called before.
+*/
+func (wac *Conn) CreateLabel(name string, color int) (Label, error) {
+	id := 0
+	for _, l := range wac.Store.GetLabels() {
+		if i, err := strconv.Atoi(l.Id); err == nil && i > id {
+			id = i
+		}
+	}
+
+	label := Label{Id: strconv.Itoa(id + 1), Name: name, Color: color}
+	if err := wac.setLabel(LabelAdded, label); err != nil {
+		return Label{}, err
+	}
+	return label, nil
+}
+
+// EditLabel changes name and color of an existing label.
+func (wac *Conn) EditLabel(label Label) error {
+	return wac.setLabel(LabelEdited, label)
+}
+
+// DeleteLabel deletes the label and removes it from all chats and messages.
+func (wac *Conn) DeleteLabel(id string) error {
+	return wac.setLabel(LabelDeleted, Label{Id: id})
+}
+
+func (wac *Conn) setLabel(action LabelAction, label Label) error {
+	attrs := map[string]string{
+		"id":   label.Id,
+		"type": string(action),
+	}
+	if action != LabelDeleted {
+		attrs["name"] = label.Name
+		attrs["color"] = strconv.Itoa(label.Color)
+	}
+
+	n := binary.Node{
+		Description: "label",
+		Attributes:  attrs,
+	}
+	if err := wac.writeLabelAction(n, string(action)+" label"); err != nil {
+		return err
+	}
+
+	wac.Store.updateLabels(LabelEvent{Action: action, Label: label})
+	return nil
+}
+
+// AddChatLabel assigns the label to the chat.
+func (wac *Conn) AddChatLabel(jid, labelId string) error {
+	return wac.assignLabel(jid, labelId, nil, true)
+}
+
+// RemoveChatLabel removes the label from the chat.
+func (wac *Conn) RemoveChatLabel(jid, labelId string) error {
+	return wac.assignLabel(jid, labelId, nil, false)
+}
+
+// AddMessageLabel assigns the label to a message of the chat.
+func (wac *Conn) AddMessageLabel(jid string, key MessageKey, labelId string) error {
+	return wac.assignLabel(jid, labelId, &key, true)
+}
+
+// RemoveMessageLabel removes the label from a message of the chat.
+func (wac *Conn) RemoveMessageLabel(jid string, key MessageKey, labelId string) error {
+	return wac.assignLabel(jid, labelId, &key, false)
+}
+
+func (wac *Conn) assignLabel(jid, labelId string, key *MessageKey, assign bool) error {
+	label := binary.Node{
+		Description: "label",
+		Attributes: map[string]string{
+			"id":   labelId,
+			"type": "add",
+		},
+	}
+	action := LabelAssigned
+	if !assign {
+		label.Attributes["type"] = "remove"
+		action = LabelUnassigned
+	}
+	if key != nil {
+		label.Attributes["index"] = key.Id
+		label.Attributes["owner"] = strconv.FormatBool(key.FromMe)
+	}
+
+	n := binary.Node{
+		Description: "chat",
+		Attributes: map[string]string{
+			"jid":  jid,
+			"type": "label",
+		},
+		Content: []binary.Node{label},
+	}
+	if err := wac.writeLabelAction(n, string(action)+" label"); err != nil {
+		return err
+	}
+
+	wac.Store.updateLabels(LabelEvent{Action: action, Label: Label{Id: labelId}, Jid: jid, Key: key})
+	return nil
+}
+
+func (wac *Conn) writeLabelAction(content binary.Node, op string) error {
+	ts := time.Now().Unix()
+	tag := fmt.Sprintf("%d.--%d", ts, wac.msgCount)
+
+	n := binary.Node{
+		Description: "action",
+		Attributes: map[string]string{
+			"type":  "set",
+			"epoch": strconv.Itoa(wac.msgCount),
+		},
+		Content: []interface{}{content},
+	}
+
+	ch, err := wac.writeBinary(n, chat, ackRequest, tag)
+	if err != nil {
+		return fmt.Errorf("could not send proto: %v", err)
+	}
+	return wac.waitChatResponse(ch, op)
+}
@@ -0,0 +1,163 @@
+package whatsapp
+
+import (
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/Rhymen/go-whatsapp/binary"
+	"github.com/Rhymen/go-whatsapp/binary/proto"
+)
+
+// LogLevel is the severity of a log entry.
+type LogLevel int
+
+const (
+	LogDebug LogLevel = iota
+	LogInfo
+	LogWarn
+	LogError
+)
+
+func (l LogLevel) String() string {
+	switch l {
+	case LogDebug:
+		return "debug"
+	case LogInfo:
+		return "info"
+	case LogWarn:
+		return "warn"
+	}
+	return "error"
+}
+
+/*
+Logger receives the log entries of a connection. keyvals are alternating keys and values adding context to the
+entry, e.g. "jid", jid, "err", err. Adapters for other logging libraries only need to implement Log.
+*/
+type Logger interface {
+	Log(level LogLevel, msg string, keyvals ...interface{})
+}
+
+/*
+NewTextLogger returns a Logger writing entries of at least the given level as lines of text to w, e.g.
+
+	2020-05-01T12:00:00Z info logged in wid=491701234567@c.us
+*/
+func NewTextLogger(w io.Writer, level LogLevel) Logger {
+	return &textLogger{w: w, level: level}
+}
+
+type textLogger struct {
+	sync.Mutex
+	w     io.Writer
+	level LogLevel
+}
+
+func (l *textLogger) Log(level LogLevel, msg string, keyvals ...interface{}) {
+	if level < l.level {
+		return
+	}
+
+	var b strings.Builder
+	fmt.Fprintf(&b, "%s %s %s", time.Now().UTC().Format(time.RFC3339), level, msg)
+	for i := 0; i < len(keyvals); i += 2 {
+		var v interface{} = "<missing>"
+		if i+1 < len(keyvals) {
+			v = keyvals[i+1]
+		}
+		fmt.Fprintf(&b, " %v=%v", keyvals[i], v)
+	}
+	b.WriteByte('\n')
+
+	l.Lock()
+	defer l.Unlock()
+	_, _ = io.WriteString(l.w, b.String())
+}
+
+func (wac *Conn) log(level LogLevel, msg string, keyvals ...interface{}) {
+	if wac.logger != nil {
+		wac.logger.Log(level, msg, keyvals...)
+	}
+}
+
+// logNode logs binary node traffic if Options.LogNodes is set.
+func (wac *Conn) logNode(direction string, tag string, node *binary.Node) {
+	if wac.logger == nil || !wac.logNodes || node == nil {
+		return
+	}
+	wac.logger.Log(LogDebug, direction+" node", "tag", tag, "node", redactNode(*node))
+}
+
+// sensitiveAttributes contains the attributes whose values are not logged.
+var sensitiveAttributes = []string{"token", "secret", "key", "auth", "code"}
+
+/*
+redactNode formats a node for logging. Message contents, binary data and the values of sensitive attributes are
+replaced by placeholders, so the log only shows the structure of the traffic.
+*/
+func redactNode(n binary.Node) string {
+	var b strings.Builder
+	writeRedactedNode(&b, n)
+	return b.String()
+}
+
+func writeRedactedNode(b *strings.Builder, n binary.Node) {
+	b.WriteString("<" + n.Description)
+
+	keys := make([]string, 0, len(n.Attributes))
+	for k := range n.Attributes {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	for _, k := range keys {
+		v := n.Attributes[k]
+		for _, s := range sensitiveAttributes {
+			if strings.Contains(strings.ToLower(k), s) {
+				v = "<redacted>"
+				break
+			}
+		}
+		fmt.Fprintf(b, " %s=%q", k, v)
+	}
+
+	switch c := n.Content.(type) {
+	case nil:
+		b.WriteString("/>")
+		return
+	case []binary.Node:
+		b.WriteString(">")
+		for _, child := range c {
+			writeRedactedNode(b, child)
+		}
+	case []interface{}:
+		b.WriteString(">")
+		for _, child := range c {
+			writeRedactedContent(b, child)
+		}
+	default:
+		b.WriteString(">")
+		writeRedactedContent(b, c)
+	}
+	b.WriteString("</" + n.Description + ">")
+}
+
+func writeRedactedContent(b *strings.Builder, content interface{}) {
+	switch c := content.(type) {
+	case binary.Node:
+		writeRedactedNode(b, c)
+	case *binary.Node:
+		writeRedactedNode(b, *c)
+	case *proto.WebMessageInfo:
+		fmt.Fprintf(b, "[message id=%s jid=%s]", c.GetKey().GetId(), c.GetKey().GetRemoteJid())
+	case []byte:
+		fmt.Fprintf(b, "[%d bytes]", len(c))
+	case string:
+		fmt.Fprintf(b, "[%d chars]", len(c))
+	default:
+		fmt.Fprintf(b, "[%T]", c)
+	}
+}
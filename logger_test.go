@@ -0,0 +1,46 @@
+package whatsapp
+
+import (
+	"bytes"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/Rhymen/go-whatsapp/binary"
+)
+
+func TestTextLogger(t *testing.T) {
+	var buf bytes.Buffer
+	l := NewTextLogger(&buf, LogInfo)
+	l.Log(LogDebug, "hidden")
+	l.Log(LogWarn, "keep alive failed", "err", errors.New("timeout"), "odd")
+
+	out := buf.String()
+	if strings.Contains(out, "hidden") {
+		t.Errorf("debug entry was logged: %q", out)
+	}
+	if !strings.HasSuffix(out, " warn keep alive failed err=timeout odd=<missing>\n") {
+		t.Errorf("unexpected log output %q", out)
+	}
+}
+
+func TestRedactNode(t *testing.T) {
+	n := binary.Node{
+		Description: "action",
+		Attributes:  map[string]string{"type": "set", "token": "s3cr3t"},
+		Content: []interface{}{
+			binary.Node{Description: "picture", Attributes: map[string]string{"jid": "a@c.us"}, Content: []binary.Node{
+				{Description: "image", Content: []byte("jpeg")},
+			}},
+		},
+	}
+
+	s := redactNode(n)
+	if strings.Contains(s, "s3cr3t") || strings.Contains(s, "jpeg") {
+		t.Errorf("sensitive content was not redacted: %s", s)
+	}
+	want := `<action token="<redacted>" type="set"><picture jid="a@c.us"><image>[4 bytes]</image></picture></action>`
+	if s != want {
+		t.Errorf("unexpected node %s", s)
+	}
+}
@@ -44,6 +44,47 @@ func Download(url string, mediaKey []byte, appInfo MediaType, fileLength int) ([
 	return data, nil
 }
 
+/*
+Download downloads and decrypts media like the package level Download function, additionally reporting the size and
+duration of the download to the Metrics of the connection. The Download methods of received media messages use it.
+*/
+func (wac *Conn) Download(url string, mediaKey []byte, appInfo MediaType, fileLength int) ([]byte, error) {
+	if wac == nil {
+		// messages that were not received by a connection
+		return Download(url, mediaKey, appInfo, fileLength)
+	}
+	start := time.Now()
+	data, err := Download(url, mediaKey, appInfo, fileLength)
+	if err != nil {
+		return nil, err
+	}
+	wac.observe(MetricMediaDownloadTime, time.Since(start).Seconds(), "type", string(appInfo))
+	wac.observe(MetricMediaDownloadSize, float64(len(data)), "type", string(appInfo))
+	return data, nil
+}
+
+// withConn attaches the connection to received media messages, so their downloads are measured.
+func (wac *Conn) withConn(event interface{}) interface{} {
+	switch m := event.(type) {
+	case ImageMessage:
+		m.wac = wac
+		return m
+	case VideoMessage:
+		m.wac = wac
+		return m
+	case AudioMessage:
+		m.wac = wac
+		return m
+	case DocumentMessage:
+		m.wac = wac
+		return m
+	case StickerMessage:
+		m.wac = wac
+		return m
+	}
+	return event
+}
+
 func validateMedia(iv []byte, file []byte, macKey []byte, mac []byte) error {
 	h := hmac.New(sha256.New, macKey)
 	n, err := h.Write(append(iv, file...))
@@ -74,7 +115,7 @@ func downloadMedia(url string) (file []byte, mac []byte, err error) {
 	}
 	defer resp.Body.Close()
 	if resp.StatusCode != http.StatusOK {
-		return nil, nil, fmt.Errorf("download failed with status code %d", resp.StatusCode)
+		return nil, nil, &ServerError{Op: "media download", Status: resp.StatusCode}
 	}
 	if resp.ContentLength <= 10 {
 		return nil, nil, fmt.Errorf("file to short")
@@ -117,11 +158,11 @@ func (wac *Conn) queryMediaConn() (hostname, auth string, ttl int, err error) {
 			return "", "", 0, fmt.Errorf("error decoding query media conn response: %v", err)
 		}
 	case <-time.After(wac.msgTimeout):
-		return "", "", 0, fmt.Errorf("query media conn timed out")
+		return "", "", 0, &TimeoutError{Op: "query media conn"}
 	}
 
-	if resp.Status != http.StatusOK {
-		return "", "", 0, fmt.Errorf("query media conn responded with %d", resp.Status)
+	if err := checkStatus("query media conn", resp.Status); err != nil {
+		return "", "", 0, err
 	}
 
 	for _, h := range resp.MediaConn.Hosts {
@@ -175,8 +216,10 @@ func (wac *Conn) Upload(reader io.Reader, appInfo MediaType) (downloadURL string
 
 	hostname, auth, _, err := wac.queryMediaConn()
 	if err != nil {
+		wac.log(LogWarn, "querying media connection failed", "err", err)
 		return "", nil, nil, nil, 0, err
 	}
+	wac.log(LogDebug, "uploading media", "type", string(appInfo), "size", fileLength, "host", hostname)
 
 	token := base64.URLEncoding.EncodeToString(fileEncSha256)
 	q := url.Values{
@@ -203,13 +246,17 @@ func (wac *Conn) Upload(reader io.Reader, appInfo MediaType) (downloadURL string
 
 	client := &http.Client{}
 	// Submit the request
+	start := time.Now()
 	res, err := client.Do(req)
 	if err != nil {
 		return "", nil, nil, nil, 0, err
 	}
+	wac.observe(MetricMediaUploadTime, time.Since(start).Seconds(), "type", string(appInfo))
+	wac.observe(MetricMediaUploadSize, float64(len(enc)+len(mac)), "type", string(appInfo))
 
 	if res.StatusCode != http.StatusOK {
-		return "", nil, nil, nil, 0, fmt.Errorf("upload failed with status code %d", res.StatusCode)
+		wac.log(LogWarn, "media upload failed", "type", string(appInfo), "status", res.StatusCode)
+		return "", nil, nil, nil, 0, &ServerError{Op: "media upload", Status: res.StatusCode}
 	}
 
 	var jsonRes map[string]string
@@ -24,39 +24,78 @@ const (
 )
 
 func (wac *Conn) Send(msg interface{}) (string, error) {
+	return wac.send(msg, nil)
+}
+
+// send sends the message. If participants are given, the message is relayed to them as a broadcast.
+func (wac *Conn) send(msg interface{}, participants []string) (string, error) {
+	msgProto, err := wac.getMessageProto(msg)
+	if err != nil {
+		return "ERROR", err
+	}
+	if wac.refuseBlocked && wac.Store.IsBlocked(msgProto.GetKey().GetRemoteJid()) {
+		return "ERROR", &ErrBlocked{Jid: msgProto.GetKey().GetRemoteJid()}
+	}
+	status := proto.WebMessageInfo_PENDING
+	msgProto.Status = &status
+	ch, err := wac.sendProto(msgProto, participants)
+	if err != nil {
+		return "ERROR", fmt.Errorf("could not send proto: %v", err)
+	}
+
+	select {
+	case response := <-ch:
+		var resp map[string]interface{}
+		if err = json.Unmarshal([]byte(response), &resp); err != nil {
+			return "ERROR", fmt.Errorf("error decoding sending response: %v\n", err)
+		}
+		if err := checkStatus("message sending", jsonStatus(resp)); err != nil {
+			return "ERROR", err
+		}
+		return getMessageInfo(msgProto).Id, nil
+	case <-time.After(wac.msgTimeout):
+		return "ERROR", &TimeoutError{Op: "sending message"}
+	}
+}
+
+// getMessageProto converts a message into its proto, uploading the media of media messages.
+func (wac *Conn) getMessageProto(msg interface{}) (*proto.WebMessageInfo, error) {
 	var msgProto *proto.WebMessageInfo
 
 	switch m := msg.(type) {
 	case *proto.WebMessageInfo:
 		msgProto = m
 	case TextMessage:
+		if wac.expandQuickReplies {
+			m.Text = wac.Store.ExpandQuickReply(m.Text)
+		}
 		msgProto = getTextProto(m)
 	case ImageMessage:
 		var err error
 		m.url, m.mediaKey, m.fileEncSha256, m.fileSha256, m.fileLength, err = wac.Upload(m.Content, MediaImage)
 		if err != nil {
-			return "ERROR", fmt.Errorf("image upload failed: %v", err)
+			return nil, fmt.Errorf("image upload failed: %v", err)
 		}
 		msgProto = getImageProto(m)
 	case VideoMessage:
 		var err error
 		m.url, m.mediaKey, m.fileEncSha256, m.fileSha256, m.fileLength, err = wac.Upload(m.Content, MediaVideo)
 		if err != nil {
-			return "ERROR", fmt.Errorf("video upload failed: %v", err)
+			return nil, fmt.Errorf("video upload failed: %v", err)
 		}
 		msgProto = getVideoProto(m)
 	case DocumentMessage:
 		var err error
 		m.url, m.mediaKey, m.fileEncSha256, m.fileSha256, m.fileLength, err = wac.Upload(m.Content, MediaDocument)
 		if err != nil {
-			return "ERROR", fmt.Errorf("document upload failed: %v", err)
+			return nil, fmt.Errorf("document upload failed: %v", err)
 		}
 		msgProto = getDocumentProto(m)
 	case AudioMessage:
 		var err error
 		m.url, m.mediaKey, m.fileEncSha256, m.fileSha256, m.fileLength, err = wac.Upload(m.Content, MediaAudio)
 		if err != nil {
-			return "ERROR", fmt.Errorf("audio upload failed: %v", err)
+			return nil, fmt.Errorf("audio upload failed: %v", err)
 		}
 		msgProto = getAudioProto(m)
 	case LocationMessage:
@@ -69,43 +108,34 @@ func (wac *Conn) Send(msg interface{}) (string, error) {
 		msgProto = getProductMessageProto(m)
 	case OrderMessage:
 		msgProto = getOrderMessageProto(m)
+	case GroupInviteMessage:
+		msgProto = getGroupInviteMessageProto(m)
 	default:
-		return "ERROR", fmt.Errorf("cannot match type %T, use message types declared in the package", msg)
-	}
-	status := proto.WebMessageInfo_PENDING
-	msgProto.Status = &status
-	ch, err := wac.sendProto(msgProto)
-	if err != nil {
-		return "ERROR", fmt.Errorf("could not send proto: %v", err)
+		return nil, fmt.Errorf("cannot match type %T, use message types declared in the package", msg)
 	}
+	return msgProto, nil
+}
 
-	select {
-	case response := <-ch:
-		var resp map[string]interface{}
-		if err = json.Unmarshal([]byte(response), &resp); err != nil {
-			return "ERROR", fmt.Errorf("error decoding sending response: %v\n", err)
-		}
-		if int(resp["status"].(float64)) != 200 {
-			return "ERROR", fmt.Errorf("message sending responded with %v", resp["status"])
-		}
-		if int(resp["status"].(float64)) == 200 {
-			return getMessageInfo(msgProto).Id, nil
+func (wac *Conn) sendProto(p *proto.WebMessageInfo, participants []string) (<-chan string, error) {
+	content := []interface{}{p}
+	if len(participants) > 0 {
+		b := binary.Node{
+			Description: "broadcast",
+			Attributes: map[string]string{
+				"jid": p.GetKey().GetRemoteJid(),
+			},
+			Content: buildParticipantNodes(participants),
 		}
-	case <-time.After(wac.msgTimeout):
-		return "ERROR", fmt.Errorf("sending message timed out")
+		content = []interface{}{b, p}
 	}
 
-	return "ERROR", nil
-}
-
-func (wac *Conn) sendProto(p *proto.WebMessageInfo) (<-chan string, error) {
 	n := binary.Node{
 		Description: "action",
 		Attributes: map[string]string{
 			"type":  "relay",
 			"epoch": strconv.Itoa(wac.msgCount),
 		},
-		Content: []interface{}{p},
+		Content: content,
 	}
 	return wac.writeBinary(n, message, ignore, p.Key.GetId())
 }
@@ -160,17 +190,10 @@ func (wac *Conn) DeleteMessage(remotejid, msgid string, fromMe bool) error {
 		if err = json.Unmarshal([]byte(response), &resp); err != nil {
 			return fmt.Errorf("error decoding deletion response: %v", err)
 		}
-		if int(resp["status"].(float64)) != 200 {
-			return fmt.Errorf("message deletion responded with %v", resp["status"])
-		}
-		if int(resp["status"].(float64)) == 200 {
-			return nil
-		}
+		return checkStatus("message deletion", jsonStatus(resp))
 	case <-time.After(wac.msgTimeout):
-		return fmt.Errorf("deleting message timed out")
+		return &TimeoutError{Op: "deleting message"}
 	}
-
-	return nil
 }
 
 func (wac *Conn) deleteChatProto(remotejid, msgid string, fromMe bool) (<-chan string, error) {
@@ -224,6 +247,8 @@ type MessageInfo struct {
 	Timestamp uint64
 	PushName  string
 	Status    MessageStatus
+	// Origin tells whether the message was received live, replayed after an offline period or loaded from the history.
+	Origin EventOrigin
 
 	Source *proto.WebMessageInfo
 }
@@ -371,6 +396,7 @@ type ImageMessage struct {
 	fileEncSha256 []byte
 	fileSha256    []byte
 	fileLength    uint64
+	wac           *Conn
 	ContextInfo   ContextInfo
 }
 
@@ -417,7 +443,7 @@ func getImageProto(msg ImageMessage) *proto.WebMessageInfo {
 Download is the function to retrieve media data. The media gets downloaded, validated and returned.
 */
 func (m *ImageMessage) Download() ([]byte, error) {
-	return Download(m.url, m.mediaKey, MediaImage, int(m.fileLength))
+	return m.wac.Download(m.url, m.mediaKey, MediaImage, int(m.fileLength))
 }
 
 /*
@@ -437,6 +463,7 @@ type VideoMessage struct {
 	fileEncSha256 []byte
 	fileSha256    []byte
 	fileLength    uint64
+	wac           *Conn
 	ContextInfo   ContextInfo
 }
 
@@ -487,7 +514,7 @@ func getVideoProto(msg VideoMessage) *proto.WebMessageInfo {
 Download is the function to retrieve media data. The media gets downloaded, validated and returned.
 */
 func (m *VideoMessage) Download() ([]byte, error) {
-	return Download(m.url, m.mediaKey, MediaVideo, int(m.fileLength))
+	return m.wac.Download(m.url, m.mediaKey, MediaVideo, int(m.fileLength))
 }
 
 /*
@@ -505,6 +532,7 @@ type AudioMessage struct {
 	fileEncSha256 []byte
 	fileSha256    []byte
 	fileLength    uint64
+	wac           *Conn
 	ContextInfo   ContextInfo
 }
 
@@ -549,7 +577,7 @@ func getAudioProto(msg AudioMessage) *proto.WebMessageInfo {
 Download is the function to retrieve media data. The media gets downloaded, validated and returned.
 */
 func (m *AudioMessage) Download() ([]byte, error) {
-	return Download(m.url, m.mediaKey, MediaAudio, int(m.fileLength))
+	return m.wac.Download(m.url, m.mediaKey, MediaAudio, int(m.fileLength))
 }
 
 /*
@@ -569,6 +597,7 @@ type DocumentMessage struct {
 	fileEncSha256 []byte
 	fileSha256    []byte
 	fileLength    uint64
+	wac           *Conn
 	ContextInfo   ContextInfo
 }
 
@@ -617,7 +646,7 @@ func getDocumentProto(msg DocumentMessage) *proto.WebMessageInfo {
 Download is the function to retrieve media data. The media gets downloaded, validated and returned.
 */
 func (m *DocumentMessage) Download() ([]byte, error) {
-	return Download(m.url, m.mediaKey, MediaDocument, int(m.fileLength))
+	return m.wac.Download(m.url, m.mediaKey, MediaDocument, int(m.fileLength))
 }
 
 /*
@@ -736,6 +765,7 @@ type StickerMessage struct {
 	fileEncSha256 []byte
 	fileSha256    []byte
 	fileLength    uint64
+	wac           *Conn
 
 	ContextInfo ContextInfo
 }
@@ -762,7 +792,7 @@ Download is the function to retrieve Sticker media data. The media gets download
 */
 
 func (m *StickerMessage) Download() ([]byte, error) {
-	return Download(m.url, m.mediaKey, MediaImage, int(m.fileLength))
+	return m.wac.Download(m.url, m.mediaKey, MediaImage, int(m.fileLength))
 }
 
 /*
@@ -855,6 +885,7 @@ func getOrderMessageProto(msg OrderMessage) *proto.WebMessageInfo {
 
 	p.Message = &proto.Message{
 		OrderMessage: &proto.OrderMessage{
+			OrderId:           &msg.OrderId,
 			Thumbnail:         msg.Thumbnail,
 			ItemCount:         &msg.ItemCount,
 			Status:            &msg.Status,
@@ -914,6 +945,85 @@ func getProductMessageProto(msg ProductMessage) *proto.WebMessageInfo {
 	return p
 }
 
+/*
+ProductInfo returns the product snapshot of the message as a Product. Only the main image is contained, the other
+images can be retrieved with Conn.GetCatalog.
+*/
+func (m ProductMessage) ProductInfo() Product {
+	prod := m.Product
+	p := Product{
+		Id:          prod.GetProductId(),
+		Name:        prod.GetTitle(),
+		Description: prod.GetDescription(),
+		Price1000:   prod.GetPriceAmount1000(),
+		Currency:    prod.GetCurrencyCode(),
+		RetailerId:  prod.GetRetailerId(),
+		URL:         prod.GetUrl(),
+	}
+	if url := prod.GetProductImage().GetUrl(); url != "" {
+		p.Images = []string{url}
+	}
+	return p
+}
+
+/*
+GroupInviteMessage represents a group invitation that was sent as a message. It can be accepted with
+Conn.AcceptGroupInvite.
+*/
+type GroupInviteMessage struct {
+	Info             MessageInfo
+	GroupJid         string
+	InviteCode       string
+	InviteExpiration int64
+	GroupName        string
+	Thumbnail        []byte
+	Caption          string
+	ContextInfo      ContextInfo
+}
+
+func getGroupInviteMessage(msg *proto.WebMessageInfo) GroupInviteMessage {
+	invite := msg.GetMessage().GetGroupInviteMessage()
+
+	groupInviteMessage := GroupInviteMessage{
+		Info:             getMessageInfo(msg),
+		GroupJid:         invite.GetGroupJid(),
+		InviteCode:       invite.GetInviteCode(),
+		InviteExpiration: invite.GetInviteExpiration(),
+		GroupName:        invite.GetGroupName(),
+		Thumbnail:        invite.GetJpegThumbnail(),
+		Caption:          invite.GetCaption(),
+		ContextInfo:      getMessageContext(invite.GetContextInfo()),
+	}
+
+	return groupInviteMessage
+}
+
+func getGroupInviteMessageProto(msg GroupInviteMessage) *proto.WebMessageInfo {
+	p := getInfoProto(&msg.Info)
+	contextInfo := getContextInfoProto(&msg.ContextInfo)
+
+	p.Message = &proto.Message{
+		GroupInviteMessage: &proto.GroupInviteMessage{
+			GroupJid:         &msg.GroupJid,
+			InviteCode:       &msg.InviteCode,
+			InviteExpiration: &msg.InviteExpiration,
+			GroupName:        &msg.GroupName,
+			JpegThumbnail:    msg.Thumbnail,
+			Caption:          &msg.Caption,
+			ContextInfo:      contextInfo,
+		},
+	}
+
+	return p
+}
+
+/*
+Expired reports whether the invitation has passed its expiration time. Invitations without an expiration never expire.
+*/
+func (m GroupInviteMessage) Expired() bool {
+	return m.InviteExpiration > 0 && time.Now().Unix() > m.InviteExpiration
+}
+
 func ParseProtoMessage(msg *proto.WebMessageInfo) interface{} {
 
 	switch {
@@ -954,6 +1064,12 @@ func ParseProtoMessage(msg *proto.WebMessageInfo) interface{} {
 	case msg.GetMessage().GetOrderMessage() != nil:
 		return getOrderMessage(msg)
 
+	case msg.GetMessage().GetGroupInviteMessage() != nil:
+		return getGroupInviteMessage(msg)
+
+	case isGroupEvent(msg):
+		return getGroupEvent(msg)
+
 	default:
 		//cannot match message
 		return ErrMessageTypeNotImplemented
@@ -997,9 +1113,40 @@ func ParseNodeMessage(msg binary.Node) interface{} {
 		return getBatteryMessage(msg.Attributes)
 	case "user":
 		return getNewContact(msg.Attributes)
+	case "chat":
+		switch msg.Attributes["type"] {
+		case "star", "unstar":
+			return getStarEvent(msg)
+		case "label":
+			return getChatLabelEvent(msg)
+		}
+	case "label":
+		return getLabelEvent(msg)
+	case "quick_reply":
+		return getQuickReplyEvent(msg)
+	case "call":
+		return getCallEvent(msg)
 	default:
 		//cannot match message
 	}
 
 	return nil
 }
+
+// nodeChildren returns the child nodes of a node's content, which is either a []binary.Node or, after unmarshalling, a
+// []interface{} of nodes and messages.
+func nodeChildren(content interface{}) []binary.Node {
+	switch c := content.(type) {
+	case []binary.Node:
+		return c
+	case []interface{}:
+		nodes := make([]binary.Node, 0, len(c))
+		for _, v := range c {
+			if n, ok := v.(binary.Node); ok {
+				nodes = append(nodes, n)
+			}
+		}
+		return nodes
+	}
+	return nil
+}
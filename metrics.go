@@ -0,0 +1,205 @@
+package whatsapp
+
+import (
+	"encoding/json"
+	"expvar"
+	"strings"
+	"sync"
+	"time"
+)
+
+/*
+Metrics receives the measurements of a connection, see Options.Metrics. labels are alternating keys and values, e.g.
+"type", "json". Counters that are decremented again, like MetricListeners, are gauges.
+*/
+type Metrics interface {
+	// Add adds delta to a counter.
+	Add(name string, delta int64, labels ...string)
+	// Observe records a value of a histogram.
+	Observe(name string, value float64, labels ...string)
+}
+
+// Names of the counters and histograms of a connection. Durations are observed in seconds, sizes in bytes.
+const (
+	MetricFramesIn          = "frames_in"
+	MetricFramesOut         = "frames_out"
+	MetricBytesIn           = "bytes_in"
+	MetricBytesOut          = "bytes_out"
+	MetricDecryptFailures   = "decrypt_failures"
+	MetricHmacFailures      = "hmac_failures"
+	MetricRequestLatency    = "request_latency_seconds"
+	MetricListeners         = "listeners"
+	MetricReconnects        = "reconnects"
+	MetricMediaUploadSize   = "media_upload_bytes"
+	MetricMediaUploadTime   = "media_upload_seconds"
+	MetricMediaDownloadSize = "media_download_bytes"
+	MetricMediaDownloadTime = "media_download_seconds"
+)
+
+var metricNames = [...]string{
+	debugLog:           "debug_log",
+	queryResume:        "query_resume",
+	queryReceipt:       "query_receipt",
+	queryMedia:         "query_media",
+	queryChat:          "query_chat",
+	queryContacts:      "query_contacts",
+	queryMessages:      "query_messages",
+	presence:           "presence",
+	presenceSubscribe:  "presence_subscribe",
+	group:              "group",
+	read:               "read",
+	chat:               "chat",
+	received:           "received",
+	pic:                "pic",
+	status:             "status",
+	message:            "message",
+	queryActions:       "query_actions",
+	block:              "block",
+	queryGroup:         "query_group",
+	queryPreview:       "query_preview",
+	queryEmoji:         "query_emoji",
+	queryMessageInfo:   "query_message_info",
+	spam:               "spam",
+	querySearch:        "query_search",
+	queryIdentity:      "query_identity",
+	queryUrl:           "query_url",
+	profile:            "profile",
+	contact:            "contact",
+	queryVcard:         "query_vcard",
+	queryStatus:        "query_status",
+	queryStatusUpdate:  "query_status_update",
+	privacyStatus:      "privacy_status",
+	queryLiveLocations: "query_live_locations",
+	liveLocation:       "live_location",
+	queryVname:         "query_vname",
+	queryLabels:        "query_labels",
+	call:               "call",
+	queryCall:          "query_call",
+	queryQuickReplies:  "query_quick_replies",
+}
+
+func (m metric) String() string {
+	if int(m) < len(metricNames) && metricNames[m] != "" {
+		return metricNames[m]
+	}
+	return "unknown"
+}
+
+func (wac *Conn) count(name string, delta int64, labels ...string) {
+	if wac.metrics != nil {
+		wac.metrics.Add(name, delta, labels...)
+	}
+}
+
+func (wac *Conn) observe(name string, value float64, labels ...string) {
+	if wac.metrics != nil {
+		wac.metrics.Observe(name, value, labels...)
+	}
+}
+
+// pendingRequest is a request waiting for its response, used to measure the request latency.
+type pendingRequest struct {
+	sent time.Time
+	kind string
+}
+
+// trackRequest starts measuring the latency of the request with the message tag.
+func (wac *Conn) trackRequest(messageTag string, kind string) {
+	if wac.metrics == nil {
+		return
+	}
+	wac.listener.Lock()
+	if wac.listener.requests == nil {
+		wac.listener.requests = make(map[string]pendingRequest)
+	}
+	wac.listener.requests[messageTag] = pendingRequest{sent: time.Now(), kind: kind}
+	wac.listener.Unlock()
+}
+
+// responseReceived observes the latency of the request answered by the message tag.
+func (wac *Conn) responseReceived(messageTag string) {
+	if wac.metrics == nil {
+		return
+	}
+	wac.listener.Lock()
+	req, ok := wac.listener.requests[messageTag]
+	delete(wac.listener.requests, messageTag)
+	wac.listener.Unlock()
+	if ok {
+		wac.observe(MetricRequestLatency, time.Since(req.sent).Seconds(), "metric", req.kind)
+	}
+}
+
+/*
+NewExpvarMetrics returns a Metrics publishing the measurements as expvar map with the given name. Histograms are
+published as count, sum, min and max. Connections using the same name share the map.
+*/
+func NewExpvarMetrics(name string) Metrics {
+	m, ok := expvar.Get(name).(*expvar.Map)
+	if !ok {
+		m = expvar.NewMap(name)
+	}
+	return &expvarMetrics{m: m}
+}
+
+type expvarMetrics struct {
+	sync.Mutex
+	m *expvar.Map
+}
+
+func metricKey(name string, labels []string) string {
+	if len(labels) == 0 {
+		return name
+	}
+	pairs := make([]string, 0, len(labels)/2)
+	for i := 0; i+1 < len(labels); i += 2 {
+		pairs = append(pairs, labels[i]+"="+labels[i+1])
+	}
+	return name + "{" + strings.Join(pairs, ",") + "}"
+}
+
+func (e *expvarMetrics) Add(name string, delta int64, labels ...string) {
+	e.m.Add(metricKey(name, labels), delta)
+}
+
+func (e *expvarMetrics) Observe(name string, value float64, labels ...string) {
+	key := metricKey(name, labels)
+
+	e.Lock()
+	h, ok := e.m.Get(key).(*expvarHistogram)
+	if !ok {
+		h = &expvarHistogram{}
+		e.m.Set(key, h)
+	}
+	e.Unlock()
+
+	h.observe(value)
+}
+
+type expvarHistogram struct {
+	sync.Mutex
+	Count int64   `json:"count"`
+	Sum   float64 `json:"sum"`
+	Min   float64 `json:"min"`
+	Max   float64 `json:"max"`
+}
+
+func (h *expvarHistogram) observe(v float64) {
+	h.Lock()
+	defer h.Unlock()
+	if h.Count == 0 || v < h.Min {
+		h.Min = v
+	}
+	if h.Count == 0 || v > h.Max {
+		h.Max = v
+	}
+	h.Count++
+	h.Sum += v
+}
+
+func (h *expvarHistogram) String() string {
+	h.Lock()
+	defer h.Unlock()
+	b, _ := json.Marshal(h)
+	return string(b)
+}
@@ -0,0 +1,98 @@
+package whatsapp
+
+import (
+	"context"
+	"encoding/json"
+	"expvar"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/gorilla/websocket"
+)
+
+type testMetrics struct {
+	counters map[string]int64
+	observed map[string]int
+}
+
+func (m *testMetrics) Add(name string, delta int64, labels ...string) {
+	m.counters[metricKey(name, labels)] += delta
+}
+
+func (m *testMetrics) Observe(name string, value float64, labels ...string) {
+	m.observed[metricKey(name, labels)]++
+}
+
+func TestRequestMetrics(t *testing.T) {
+	m := &testMetrics{counters: map[string]int64{}, observed: map[string]int{}}
+	wac := &Conn{metrics: m, listener: &listenerWrapper{m: map[string]chan string{}}}
+
+	ch := make(chan string, 1)
+	wac.addListener(ch, "1.--0")
+	wac.trackRequest("1.--0", group.String())
+	if m.counters[MetricListeners] != 1 {
+		t.Errorf("expected 1 listener, got %d", m.counters[MetricListeners])
+	}
+
+	if err := wac.processReadData(websocket.TextMessage, []byte(`1.--0,{"status":200}`)); err != nil {
+		t.Fatal(err)
+	}
+	if r := <-ch; r != `{"status":200}` {
+		t.Errorf("unexpected response %q", r)
+	}
+
+	if m.counters[MetricListeners] != 0 {
+		t.Errorf("expected no listeners, got %d", m.counters[MetricListeners])
+	}
+	if m.counters["frames_in{type=json}"] != 1 || m.counters[MetricBytesIn] != 20 {
+		t.Errorf("unexpected counters %v", m.counters)
+	}
+	if m.observed["request_latency_seconds{metric=group}"] != 1 {
+		t.Errorf("request latency not observed: %v", m.observed)
+	}
+}
+
+func TestExpvarMetrics(t *testing.T) {
+	// expvar maps are global, use a new one for every run
+	name := fmt.Sprintf("whatsapp_test_%d", time.Now().UnixNano())
+	m := NewExpvarMetrics(name)
+	m.Add(MetricFramesIn, 2, "type", "binary")
+	m.Observe(MetricMediaUploadSize, 10, "type", "image")
+	m.Observe(MetricMediaUploadSize, 30, "type", "image")
+
+	v := expvar.Get(name).(*expvar.Map)
+	if c := v.Get("frames_in{type=binary}").String(); c != "2" {
+		t.Errorf("unexpected counter %s", c)
+	}
+	var h struct {
+		Count int64
+		Sum   float64
+		Min   float64
+		Max   float64
+	}
+	if err := json.Unmarshal([]byte(v.Get("media_upload_bytes{type=image}").String()), &h); err != nil {
+		t.Fatal(err)
+	}
+	if h.Count != 2 || h.Sum != 40 || h.Min != 10 || h.Max != 30 {
+		t.Errorf("unexpected histogram %+v", h)
+	}
+}
+
+func TestMediaMessagesUseConn(t *testing.T) {
+	wac := &Conn{}
+	var image ImageMessage
+	On(wac, func(ctx context.Context, m ImageMessage) {
+		image = m
+	}, Synchronously())
+
+	wac.handle(ImageMessage{Caption: "a"})
+	if image.wac != wac {
+		t.Error("expected received image to download with the connection")
+	}
+
+	// media messages without a connection still download with the package level function
+	if _, err := (&ImageMessage{}).Download(); err == nil || err.Error() != "no url present" {
+		t.Errorf("unexpected error %v", err)
+	}
+}
@@ -0,0 +1,196 @@
+package whatsapp
+
+import (
+	"context"
+	"reflect"
+	"runtime/debug"
+	"time"
+
+	"github.com/Rhymen/go-whatsapp/binary/proto"
+)
+
+/*
+HandlerFunc is a single invocation of a handler, or of the rest of the middleware chain, for an event. Errors
+returned by it are passed to HandleError.
+*/
+type HandlerFunc func(ctx context.Context, event interface{}) error
+
+/*
+Middleware wraps every invocation of a handler or event bus subscriber. It can skip the invocation by not calling
+next, e.g. to filter events, or act before and after it.
+*/
+type Middleware func(next HandlerFunc) HandlerFunc
+
+/*
+Use appends middleware to the chain wrapping every handler invocation. The first middleware is the outermost one.
+Connections created with NewConnWithOptions start with Recover. Use is not safe to call while events are dispatched,
+middleware should be added before logging in.
+*/
+func (wac *Conn) Use(middleware ...Middleware) {
+	wac.middleware = append(wac.middleware, middleware...)
+}
+
+// invoke runs the handler call for the event through the middleware chain.
+func (wac *Conn) invoke(ctx context.Context, event interface{}, call func(ctx context.Context)) {
+	h := func(ctx context.Context, event interface{}) error {
+		call(ctx)
+		return nil
+	}
+	for i := len(wac.middleware) - 1; i >= 0; i-- {
+		h = wac.middleware[i](h)
+	}
+	if err := h(ctx, event); err != nil {
+		wac.reportDispatchError(err)
+	}
+}
+
+// Recover converts panics of handlers into an *ErrHandlerPanic that is passed to HandleError.
+func Recover() Middleware {
+	return func(next HandlerFunc) HandlerFunc {
+		return func(ctx context.Context, event interface{}) (err error) {
+			defer func() {
+				if r := recover(); r != nil {
+					err = &ErrHandlerPanic{Event: event, Value: r, Stack: debug.Stack()}
+				}
+			}()
+			return next(ctx, event)
+		}
+	}
+}
+
+// LogEvents logs every handler invocation and its error with printf, e.g. log.Printf.
+func LogEvents(printf func(format string, v ...interface{})) Middleware {
+	return func(next HandlerFunc) HandlerFunc {
+		return func(ctx context.Context, event interface{}) error {
+			if chat := eventChat(event); chat != "" {
+				printf("handling %T of chat %s", event, chat)
+			} else {
+				printf("handling %T", event)
+			}
+			err := next(ctx, event)
+			if err != nil {
+				printf("handling %T failed: %v", event, err)
+			}
+			return err
+		}
+	}
+}
+
+// Timing reports the duration of every handler invocation to observe.
+func Timing(observe func(event interface{}, d time.Duration)) Middleware {
+	return func(next HandlerFunc) HandlerFunc {
+		return func(ctx context.Context, event interface{}) error {
+			start := time.Now()
+			err := next(ctx, event)
+			observe(event, time.Since(start))
+			return err
+		}
+	}
+}
+
+// Filter skips the handlers for events for which accept returns false.
+func Filter(accept func(event interface{}) bool) Middleware {
+	return func(next HandlerFunc) HandlerFunc {
+		return func(ctx context.Context, event interface{}) error {
+			if !accept(event) {
+				return nil
+			}
+			return next(ctx, event)
+		}
+	}
+}
+
+// FilterChats skips the handlers for events of chats other than jids. Events that do not belong to a chat are handled.
+func FilterChats(jids ...string) Middleware {
+	allowed := stringSet(jids)
+	return Filter(func(event interface{}) bool {
+		chat := eventChat(event)
+		return chat == "" || allowed[chat]
+	})
+}
+
+/*
+FilterSenders skips the handlers for messages sent by the account itself or by senders other than jids. Other events
+are handled.
+*/
+func FilterSenders(jids ...string) Middleware {
+	allowed := stringSet(jids)
+	return Filter(func(event interface{}) bool {
+		info, ok := eventInfo(event)
+		return !ok || (!info.FromMe && allowed[info.Sender()])
+	})
+}
+
+// SkipOwnMessages skips the handlers for messages sent by the account itself.
+func SkipOwnMessages() Middleware {
+	return Filter(func(event interface{}) bool {
+		info, ok := eventInfo(event)
+		return !ok || !info.FromMe
+	})
+}
+
+/*
+SkipOldMessages skips the handlers for messages sent before the connection logged in, e.g. messages received while
+offline or loaded from the chat history.
+*/
+func SkipOldMessages() Middleware {
+	return func(next HandlerFunc) HandlerFunc {
+		return func(ctx context.Context, event interface{}) error {
+			info, ok := eventInfo(event)
+			if wac := ConnFromContext(ctx); ok && wac != nil && int64(info.Timestamp) < wac.loginTime.Unix() {
+				return nil
+			}
+			return next(ctx, event)
+		}
+	}
+}
+
+// Sender returns the jid of the sender of a received message, which is the chat itself for messages of private chats.
+func (info MessageInfo) Sender() string {
+	if info.SenderJid != "" {
+		return info.SenderJid
+	}
+	return info.RemoteJid
+}
+
+func stringSet(s []string) map[string]bool {
+	set := make(map[string]bool, len(s))
+	for _, v := range s {
+		set[v] = true
+	}
+	return set
+}
+
+var messageInfoType = reflect.TypeOf(MessageInfo{})
+
+// eventInfo returns the MessageInfo of message events.
+func eventInfo(event interface{}) (MessageInfo, bool) {
+	if msg, ok := event.(*proto.WebMessageInfo); ok {
+		return getMessageInfo(msg), true
+	}
+
+	// message types carry their info in the Info field
+	v := reflect.ValueOf(event)
+	if v.Kind() == reflect.Struct {
+		if info := v.FieldByName("Info"); info.IsValid() && info.Type() == messageInfoType {
+			return info.Interface().(MessageInfo), true
+		}
+	}
+	return MessageInfo{}, false
+}
+
+// withInfo returns a copy of a message event with its MessageInfo changed by set, ok is false for other events.
+func withInfo(event interface{}, set func(info *MessageInfo)) (_ interface{}, ok bool) {
+	v := reflect.ValueOf(event)
+	if v.Kind() != reflect.Struct {
+		return event, false
+	}
+	if info := v.FieldByName("Info"); !info.IsValid() || info.Type() != messageInfoType {
+		return event, false
+	}
+
+	c := reflect.New(v.Type()).Elem()
+	c.Set(v)
+	set(c.FieldByName("Info").Addr().Interface().(*MessageInfo))
+	return c.Interface(), true
+}
@@ -0,0 +1,44 @@
+package whatsapp
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+type errorHandler chan error
+
+func (h errorHandler) HandleError(err error) { h <- err }
+
+func TestMiddleware(t *testing.T) {
+	errs := make(errorHandler, 1)
+	wac := &Conn{handler: []Handler{errs}, loginTime: time.Unix(1000, 0)}
+	wac.Use(Recover(), SkipOwnMessages(), SkipOldMessages(), FilterChats("a@s.whatsapp.net"))
+
+	var texts []string
+	On(wac, func(ctx context.Context, m TextMessage) {
+		if m.Text == "panic" {
+			panic("boom")
+		}
+		texts = append(texts, m.Text)
+	}, Synchronously())
+
+	info := MessageInfo{RemoteJid: "a@s.whatsapp.net", Timestamp: 1000}
+	wac.handle(TextMessage{Info: info, Text: "ok"})
+	wac.handle(TextMessage{Info: MessageInfo{RemoteJid: "b@s.whatsapp.net", Timestamp: 1000}, Text: "other chat"})
+	wac.handle(TextMessage{Info: MessageInfo{RemoteJid: "a@s.whatsapp.net", Timestamp: 999}, Text: "old"})
+	wac.handle(TextMessage{Info: MessageInfo{RemoteJid: "a@s.whatsapp.net", Timestamp: 1000, FromMe: true}, Text: "own"})
+	wac.handle(TextMessage{Info: info, Text: "panic"})
+
+	if len(texts) != 1 || texts[0] != "ok" {
+		t.Errorf("unexpected texts %v", texts)
+	}
+	select {
+	case err := <-errs:
+		if p, ok := err.(*ErrHandlerPanic); !ok || p.Value != "boom" {
+			t.Errorf("expected handler panic, got %v", err)
+		}
+	case <-time.After(time.Second):
+		t.Error("panic was not reported")
+	}
+}
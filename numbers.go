@@ -0,0 +1,167 @@
+package whatsapp
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+)
+
+// NumberCheckResult is the result of checking whether a phone number is registered on WhatsApp.
+type NumberCheckResult struct {
+	// Number is the phone number as passed to CheckNumbers.
+	Number string
+	// Jid is the jid the number was normalized to.
+	Jid string
+	// Exists is set if the number is registered on WhatsApp.
+	Exists bool
+	// CanonicalJid is the jid of the account reported by the server, which may differ from Jid.
+	CanonicalJid string
+	// Business is set if the account is a WhatsApp Business account.
+	Business bool
+	// Err is set if the number is invalid or the query failed.
+	Err error
+}
+
+/*
+CheckNumbersOptions controls the rate at which CheckNumbersWithOptions queries the server. Every number is a separate
+exist query, Concurrency limits the number of queries waiting for a response, Interval is the minimum time between
+starting two queries.
+*/
+type CheckNumbersOptions struct {
+	Concurrency int
+	Interval    time.Duration
+}
+
+var defaultCheckNumbersOptions = CheckNumbersOptions{
+	Concurrency: 4,
+	Interval:    100 * time.Millisecond,
+}
+
+/*
+NumberToJid converts a phone number in E.164 format to a jid. Spaces, dashes, dots and parentheses are ignored, the
+number may start with "+" or "00".
+*/
+func NumberToJid(number string) (string, error) {
+	n := strings.Map(func(r rune) rune {
+		switch r {
+		case ' ', '-', '.', '(', ')', '/':
+			return -1
+		}
+		return r
+	}, strings.TrimSpace(number))
+
+	if strings.HasPrefix(n, "+") {
+		n = n[1:]
+	} else if strings.HasPrefix(n, "00") {
+		n = n[2:]
+	}
+
+	if len(n) < 7 || len(n) > 15 || n[0] == '0' {
+		return "", fmt.Errorf("invalid phone number %q", number)
+	}
+	for _, r := range n {
+		if r < '0' || r > '9' {
+			return "", fmt.Errorf("invalid phone number %q", number)
+		}
+	}
+
+	return n + "@s.whatsapp.net", nil
+}
+
+/*
+CheckNumbers checks which phone numbers are registered on WhatsApp, using the default rate limits. The server is
+queried once per number, so checking n numbers takes at least n times the default Interval.
+*/
+func (wac *Conn) CheckNumbers(numbers []string) []NumberCheckResult {
+	return wac.CheckNumbersWithOptions(numbers, defaultCheckNumbersOptions)
+}
+
+/*
+CheckNumbersWithOptions checks which phone numbers are registered on WhatsApp. The numbers are normalized with
+NumberToJid and each number is looked up with its own exist query, the queries run concurrently within the limits of
+opt. There is no multi-number query, so large lists should use an Interval that keeps the account below the rate
+limits of the server. The results are returned in the order of numbers.
+*/
+func (wac *Conn) CheckNumbersWithOptions(numbers []string, opt CheckNumbersOptions) []NumberCheckResult {
+	if opt.Concurrency <= 0 {
+		opt.Concurrency = 1
+	}
+
+	results := make([]NumberCheckResult, len(numbers))
+	jobs := make(chan int)
+
+	var pace <-chan time.Time
+	if opt.Interval > 0 {
+		ticker := time.NewTicker(opt.Interval)
+		defer ticker.Stop()
+		pace = ticker.C
+	}
+
+	wg := sync.WaitGroup{}
+	for w := 0; w < opt.Concurrency; w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range jobs {
+				results[i] = wac.checkNumber(results[i])
+			}
+		}()
+	}
+
+	for i, number := range numbers {
+		results[i].Number = number
+		results[i].Jid, results[i].Err = NumberToJid(number)
+		if results[i].Err != nil {
+			continue
+		}
+		if pace != nil {
+			<-pace
+		}
+		jobs <- i
+	}
+	close(jobs)
+	wg.Wait()
+
+	return results
+}
+
+func (wac *Conn) checkNumber(result NumberCheckResult) NumberCheckResult {
+	ch, err := wac.Exist(result.Jid)
+	if err != nil {
+		result.Err = err
+		return result
+	}
+
+	var resp struct {
+		Status int    `json:"status"`
+		Jid    string `json:"jid"`
+		Biz    bool   `json:"biz"`
+	}
+	select {
+	case r := <-ch:
+		if err := json.Unmarshal([]byte(r), &resp); err != nil {
+			result.Err = fmt.Errorf("error decoding exist response: %v", err)
+			return result
+		}
+	case <-time.After(wac.msgTimeout):
+		result.Err = &TimeoutError{Op: "exist request"}
+		return result
+	}
+
+	switch resp.Status {
+	case 200:
+		result.Exists = true
+		result.CanonicalJid = toSWhatsAppJid(resp.Jid)
+		if result.CanonicalJid == "" {
+			result.CanonicalJid = result.Jid
+		}
+		result.Business = resp.Biz
+	case 404:
+	default:
+		result.Err = &ServerError{Op: "exist request", Status: resp.Status}
+	}
+
+	return result
+}
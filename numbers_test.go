@@ -0,0 +1,25 @@
+package whatsapp
+
+import (
+	"testing"
+)
+
+func TestNumberToJid(t *testing.T) {
+	valid := map[string]string{
+		"+49 170 1234567":   "491701234567@s.whatsapp.net",
+		"0049-170-1234567":  "491701234567@s.whatsapp.net",
+		"1 (555) 123.4567":  "15551234567@s.whatsapp.net",
+		"  +5511987654321 ": "5511987654321@s.whatsapp.net",
+	}
+	for number, want := range valid {
+		if jid, err := NumberToJid(number); err != nil || jid != want {
+			t.Errorf("NumberToJid(%q) = %q, %v, want %q", number, jid, err, want)
+		}
+	}
+
+	for _, number := range []string{"", "+49 170", "0170 1234567", "+49 170 12345a7", "+1234567890123456"} {
+		if jid, err := NumberToJid(number); err == nil {
+			t.Errorf("NumberToJid(%q) = %q, expected error", number, jid)
+		}
+	}
+}
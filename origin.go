@@ -0,0 +1,71 @@
+package whatsapp
+
+import (
+	"context"
+)
+
+// EventOrigin tells whether an event happened while connected or was received later.
+type EventOrigin int
+
+const (
+	// OriginLive events are received as they happen.
+	OriginLive EventOrigin = iota
+	// OriginOffline events happened while the connection was offline and are replayed by the phone after login.
+	OriginOffline
+	// OriginHistory events are messages loaded from the chat history, e.g. with LoadChatMessages.
+	OriginHistory
+)
+
+func (o EventOrigin) String() string {
+	switch o {
+	case OriginOffline:
+		return "offline"
+	case OriginHistory:
+		return "history"
+	}
+	return "live"
+}
+
+/*
+actionOrigin determines the origin of the messages of an action node. Live messages are relayed, offline messages
+are sent in chunks marked "before" and "last" for the final chunk.
+*/
+func actionOrigin(attributes map[string]string) EventOrigin {
+	switch attributes["add"] {
+	case "before", "last", "after", "unread":
+		return OriginOffline
+	}
+	return OriginLive
+}
+
+type originContextKey struct{}
+
+// OriginFromContext returns the origin of the event of a handler context.
+func OriginFromContext(ctx context.Context) EventOrigin {
+	origin, _ := ctx.Value(originContextKey{}).(EventOrigin)
+	return origin
+}
+
+// withOrigin sets the Origin of the MessageInfo of message events.
+func withOrigin(event interface{}, origin EventOrigin) interface{} {
+	if origin == OriginLive {
+		return event
+	}
+
+	event, _ = withInfo(event, func(info *MessageInfo) {
+		info.Origin = origin
+	})
+	return event
+}
+
+// SkipReplayed skips the handlers for events that were replayed after an offline period or loaded from the history.
+func SkipReplayed() Middleware {
+	return func(next HandlerFunc) HandlerFunc {
+		return func(ctx context.Context, event interface{}) error {
+			if OriginFromContext(ctx) != OriginLive {
+				return nil
+			}
+			return next(ctx, event)
+		}
+	}
+}
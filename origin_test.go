@@ -0,0 +1,46 @@
+package whatsapp
+
+import (
+	"context"
+	"testing"
+
+	"github.com/Rhymen/go-whatsapp/binary"
+	"github.com/Rhymen/go-whatsapp/binary/proto"
+)
+
+func TestDispatchOrigin(t *testing.T) {
+	wac := &Conn{}
+
+	var texts []TextMessage
+	var origins []EventOrigin
+	On(wac, func(ctx context.Context, m TextMessage) {
+		texts = append(texts, m)
+		origins = append(origins, OriginFromContext(ctx))
+	}, Synchronously())
+
+	action := func(add string) *binary.Node {
+		jid, id, text := "a@s.whatsapp.net", "1", "hi"
+		return &binary.Node{
+			Description: "action",
+			Attributes:  map[string]string{"add": add},
+			Content: []interface{}{&proto.WebMessageInfo{
+				Key:     &proto.MessageKey{RemoteJid: &jid, Id: &id},
+				Message: &proto.Message{Conversation: &text},
+			}},
+		}
+	}
+	wac.dispatch(action("relay"))
+	wac.dispatch(action("before"))
+	wac.dispatch(action("last"))
+	wac.handleWithCustomHandlers(TextMessage{Text: "old"}, nil)
+
+	want := []EventOrigin{OriginLive, OriginOffline, OriginOffline, OriginHistory}
+	if len(texts) != len(want) {
+		t.Fatalf("expected %d messages, got %d", len(want), len(texts))
+	}
+	for i, origin := range want {
+		if texts[i].Info.Origin != origin || origins[i] != origin {
+			t.Errorf("message %d: expected origin %v, got %v and %v in context", i, origin, texts[i].Info.Origin, origins[i])
+		}
+	}
+}
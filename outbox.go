@@ -0,0 +1,474 @@
+package whatsapp
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/Rhymen/go-whatsapp/binary/proto"
+	protobuf "github.com/golang/protobuf/proto"
+)
+
+const (
+	defaultOutboxRetryInterval = 10 * time.Second
+	// outboxTrackingTime is the time receipts of sent messages are reported to the status callbacks.
+	outboxTrackingTime = 24 * time.Hour
+)
+
+/*
+OutboxStore persists the pending messages of an Outbox, so they are not lost if the program is restarted before the
+servers acknowledged them. Messages are saved with status PENDING before they are sent the first time and deleted once
+they are acknowledged or failed permanently.
+*/
+type OutboxStore interface {
+	Save(msg *proto.WebMessageInfo) error
+	Delete(id string) error
+	// Load returns the saved messages in the order they were saved.
+	Load() ([]*proto.WebMessageInfo, error)
+}
+
+/*
+NewFileOutboxStore returns an OutboxStore saving every pending message as a file in dir. The directory is created if
+it does not exist. Like a recording, the files contain message contents and should be handled like the session.
+*/
+func NewFileOutboxStore(dir string) (OutboxStore, error) {
+	if err := os.MkdirAll(dir, 0700); err != nil {
+		return nil, err
+	}
+	return &fileOutboxStore{dir: dir}, nil
+}
+
+type fileOutboxStore struct {
+	sync.Mutex
+	dir  string
+	last int64
+}
+
+// files returns the files of the message with the id. Files are named by the time they were saved and the id.
+func (s *fileOutboxStore) files(id string) ([]string, error) {
+	return filepath.Glob(filepath.Join(s.dir, "*_"+filepath.Base(id)+".msg"))
+}
+
+func (s *fileOutboxStore) Save(msg *proto.WebMessageInfo) error {
+	data, err := protobuf.Marshal(msg)
+	if err != nil {
+		return err
+	}
+
+	s.Lock()
+	n := time.Now().UnixNano()
+	if n <= s.last {
+		n = s.last + 1
+	}
+	s.last = n
+	s.Unlock()
+
+	// write to a temporary file first, so a crash never leaves a partially written message
+	path := filepath.Join(s.dir, fmt.Sprintf("%019d_%s.msg", n, filepath.Base(msg.GetKey().GetId())))
+	if err := ioutil.WriteFile(path+".tmp", data, 0600); err != nil {
+		return err
+	}
+	return os.Rename(path+".tmp", path)
+}
+
+func (s *fileOutboxStore) Delete(id string) error {
+	files, err := s.files(id)
+	if err != nil {
+		return err
+	}
+	for _, f := range files {
+		if err := os.Remove(f); err != nil && !os.IsNotExist(err) {
+			return err
+		}
+	}
+	return nil
+}
+
+func (s *fileOutboxStore) Load() ([]*proto.WebMessageInfo, error) {
+	// ReadDir sorts the files by name, which is the order they were saved in
+	files, err := ioutil.ReadDir(s.dir)
+	if err != nil {
+		return nil, err
+	}
+
+	var msgs []*proto.WebMessageInfo
+	for _, f := range files {
+		if f.IsDir() || !strings.HasSuffix(f.Name(), ".msg") {
+			continue
+		}
+		data, err := ioutil.ReadFile(filepath.Join(s.dir, f.Name()))
+		if err != nil {
+			return nil, err
+		}
+		msg := &proto.WebMessageInfo{}
+		if err := protobuf.Unmarshal(data, msg); err != nil {
+			return nil, err
+		}
+		msgs = append(msgs, msg)
+	}
+	return msgs, nil
+}
+
+// StatusFunc is called when the status of a message sent with an Outbox changes. err is set if the status is Error.
+type StatusFunc func(id string, status MessageStatus, err error)
+
+// OutboxOptions configures an Outbox.
+type OutboxOptions struct {
+	// Store persists the pending messages. Messages are only kept in memory if it is nil.
+	Store OutboxStore
+	// RetryInterval is the time between attempts to send a message, 10 seconds by default.
+	RetryInterval time.Duration
+	// MaxAttempts limits the attempts to send a message while logged in, 0 retries until the message is acknowledged.
+	MaxAttempts int
+	// OnStatus is called for the status changes of all messages, including messages loaded from the store.
+	OnStatus StatusFunc
+}
+
+/*
+Outbox queues messages and sends them while the connection is logged in. Messages that could not be sent, e.g.
+because the connection was lost or the request timed out, are sent again with the same message id after the
+RetryInterval or as soon as the connection logged in again, so the servers drop duplicates. Messages are sent in the
+order they were queued.
+
+The status of a message is reported once for every change: ServerAck when the servers accepted it, DeliveryAck, Read
+and Played from the receipts of the recipients, and Error if it failed permanently or the servers answered with an
+error ack. Receipts of group messages report the highest status of any participant.
+*/
+type Outbox struct {
+	wac *Conn
+	opt OutboxOptions
+	sub *Subscription
+
+	sync.Mutex
+	entries map[string]*outboxEntry
+	seq     int
+
+	wakeCh    chan struct{}
+	done      chan struct{}
+	closeOnce sync.Once
+}
+
+type outboxEntry struct {
+	msg      *proto.WebMessageInfo
+	seq      int
+	status   MessageStatus
+	attempts int
+	next     time.Time
+	acked    time.Time
+	onStatus StatusFunc
+}
+
+/*
+NewOutbox creates an outbox for the connection and queues the pending messages of the store. A connection has a
+single outbox that is woken up after Login and Restore, creating another one replaces it.
+*/
+func NewOutbox(wac *Conn, opt OutboxOptions) (*Outbox, error) {
+	if opt.RetryInterval <= 0 {
+		opt.RetryInterval = defaultOutboxRetryInterval
+	}
+
+	o := &Outbox{
+		wac:     wac,
+		opt:     opt,
+		entries: make(map[string]*outboxEntry),
+		wakeCh:  make(chan struct{}, 1),
+		done:    make(chan struct{}),
+	}
+
+	if opt.Store != nil {
+		msgs, err := opt.Store.Load()
+		if err != nil {
+			return nil, err
+		}
+		sort.SliceStable(msgs, func(i, j int) bool {
+			return msgs[i].GetMessageTimestamp() < msgs[j].GetMessageTimestamp()
+		})
+		for _, msg := range msgs {
+			o.add(msg, nil)
+		}
+	}
+
+	o.sub = On(wac, o.handleReceipt, Synchronously())
+	wac.outbox.set(o)
+	go o.run()
+	o.wake()
+	return o, nil
+}
+
+/*
+Send converts the message like Conn.Send, persists it and queues it for sending. The message id is returned
+immediately, onStatus is called for the status changes of this message in addition to OutboxOptions.OnStatus. Unlike
+the OnStatus option, onStatus is lost if the program is restarted.
+*/
+func (o *Outbox) Send(msg interface{}, onStatus StatusFunc) (string, error) {
+	msgProto, err := o.wac.getMessageProto(msg)
+	if err != nil {
+		return "ERROR", err
+	}
+	if o.wac.refuseBlocked && o.wac.Store.IsBlocked(msgProto.GetKey().GetRemoteJid()) {
+		return "ERROR", &ErrBlocked{Jid: msgProto.GetKey().GetRemoteJid()}
+	}
+	status := proto.WebMessageInfo_PENDING
+	msgProto.Status = &status
+
+	if o.opt.Store != nil {
+		if err := o.opt.Store.Save(msgProto); err != nil {
+			return "ERROR", err
+		}
+	}
+
+	o.add(msgProto, onStatus)
+	o.wake()
+	return msgProto.GetKey().GetId(), nil
+}
+
+// Status returns the last status of a message sent with the outbox.
+func (o *Outbox) Status(id string) (MessageStatus, bool) {
+	defer o.Unlock()
+	o.Lock()
+	e, ok := o.entries[id]
+	if !ok {
+		return Error, false
+	}
+	return e.status, true
+}
+
+// Pending returns the ids of the messages that were not acknowledged by the servers yet, in the order they are sent.
+func (o *Outbox) Pending() []string {
+	defer o.Unlock()
+	o.Lock()
+	var pending []*outboxEntry
+	for _, e := range o.entries {
+		if e.status == Pending {
+			pending = append(pending, e)
+		}
+	}
+	sort.Slice(pending, func(i, j int) bool {
+		return pending[i].seq < pending[j].seq
+	})
+
+	ids := make([]string, len(pending))
+	for i, e := range pending {
+		ids[i] = e.msg.GetKey().GetId()
+	}
+	return ids
+}
+
+// Close stops sending and detaches the outbox from the connection. Pending messages stay in the store.
+func (o *Outbox) Close() {
+	o.closeOnce.Do(func() {
+		o.sub.Unsubscribe()
+		o.wac.outbox.remove(o)
+		close(o.done)
+	})
+}
+
+func (o *Outbox) add(msg *proto.WebMessageInfo, onStatus StatusFunc) {
+	defer o.Unlock()
+	o.Lock()
+	o.seq++
+	o.entries[msg.GetKey().GetId()] = &outboxEntry{
+		msg:      msg,
+		seq:      o.seq,
+		status:   Pending,
+		onStatus: onStatus,
+	}
+}
+
+func (o *Outbox) wake() {
+	select {
+	case o.wakeCh <- struct{}{}:
+	default:
+	}
+}
+
+func (o *Outbox) run() {
+	t := time.NewTicker(o.opt.RetryInterval)
+	defer t.Stop()
+	for {
+		select {
+		case <-o.done:
+			return
+		case <-t.C:
+		case <-o.wakeCh:
+		}
+		o.flush()
+	}
+}
+
+// flush sends the due pending messages one after another, so they arrive in order.
+func (o *Outbox) flush() {
+	if !o.wac.IsLoggedIn() {
+		return
+	}
+
+	for _, e := range o.due(time.Now()) {
+		select {
+		case <-o.done:
+			return
+		default:
+		}
+		o.result(e, o.attempt(e))
+	}
+}
+
+// due returns the pending messages to send and removes acknowledged messages that are no longer tracked.
+func (o *Outbox) due(now time.Time) []*outboxEntry {
+	defer o.Unlock()
+	o.Lock()
+
+	var due []*outboxEntry
+	for id, e := range o.entries {
+		if e.status != Pending {
+			if now.Sub(e.acked) > outboxTrackingTime {
+				delete(o.entries, id)
+			}
+			continue
+		}
+		if !now.Before(e.next) {
+			e.attempts++
+			due = append(due, e)
+		}
+	}
+	sort.Slice(due, func(i, j int) bool {
+		return due[i].seq < due[j].seq
+	})
+	return due
+}
+
+func (o *Outbox) attempt(e *outboxEntry) error {
+	ch, err := o.wac.sendProto(e.msg, nil)
+	if err != nil {
+		return err
+	}
+
+	select {
+	case response := <-ch:
+		var resp map[string]interface{}
+		if err := json.Unmarshal([]byte(response), &resp); err != nil {
+			return err
+		}
+		return checkStatus("message sending", jsonStatus(resp))
+	case <-time.After(o.wac.msgTimeout):
+		return &TimeoutError{Op: "sending message"}
+	case <-o.done:
+		return ErrNotConnected
+	}
+}
+
+// result handles the outcome of an attempt, failed messages are retried unless the error is permanent.
+func (o *Outbox) result(e *outboxEntry, err error) {
+	id := e.msg.GetKey().GetId()
+	if err == nil {
+		o.update(id, ServerAck, nil)
+		return
+	}
+
+	o.Lock()
+	retry := retryable(err) && (o.opt.MaxAttempts <= 0 || e.attempts < o.opt.MaxAttempts)
+	if retry {
+		e.next = time.Now().Add(o.opt.RetryInterval)
+	}
+	o.Unlock()
+
+	if retry {
+		o.wac.log(LogWarn, "sending queued message failed, retrying", "id", id, "attempt", e.attempts, "err", err)
+		return
+	}
+	o.wac.log(LogError, "sending queued message failed", "id", id, "attempt", e.attempts, "err", err)
+	o.update(id, Error, err)
+}
+
+// retryable reports whether sending a message could succeed later. Requests the servers refused are not retried,
+// except if they were rate limited or failed because of a server error.
+func retryable(err error) bool {
+	var serverErr *ServerError
+	if errors.As(err, &serverErr) {
+		return serverErr.Status == http.StatusTooManyRequests || serverErr.Status >= 500
+	}
+	return true
+}
+
+// handleReceipt updates the status from acks, an error ack fails the message.
+func (o *Outbox) handleReceipt(ctx context.Context, receipt ReceiptEvent) {
+	if receipt.Status == Pending {
+		return
+	}
+	for _, id := range receipt.Ids {
+		var err error
+		if receipt.Status == Error {
+			err = fmt.Errorf("message %s was rejected by the server", id)
+		}
+		o.update(id, receipt.Status, err)
+	}
+}
+
+/*
+update sets the status of a message and calls the status callbacks. Acks are received for every attempt and receipt,
+so statuses that are not higher than the current one are ignored.
+*/
+func (o *Outbox) update(id string, status MessageStatus, err error) {
+	o.Lock()
+	e, ok := o.entries[id]
+	if !ok || e.status == Error || (status != Error && status <= e.status) {
+		o.Unlock()
+		return
+	}
+	wasPending := e.status == Pending
+	e.status = status
+	if wasPending {
+		e.acked = time.Now()
+	}
+	onStatus := e.onStatus
+	o.Unlock()
+
+	if wasPending && o.opt.Store != nil {
+		if err := o.opt.Store.Delete(id); err != nil {
+			o.wac.log(LogWarn, "deleting queued message failed", "id", id, "err", err)
+		}
+	}
+
+	if onStatus != nil {
+		onStatus(id, status, err)
+	}
+	if o.opt.OnStatus != nil {
+		o.opt.OnStatus(id, status, err)
+	}
+}
+
+// outboxRef is the outbox of a connection, which is woken up after logging in.
+type outboxRef struct {
+	sync.Mutex
+	o *Outbox
+}
+
+func (r *outboxRef) set(o *Outbox) {
+	defer r.Unlock()
+	r.Lock()
+	r.o = o
+}
+
+func (r *outboxRef) remove(o *Outbox) {
+	defer r.Unlock()
+	r.Lock()
+	if r.o == o {
+		r.o = nil
+	}
+}
+
+func (r *outboxRef) wake() {
+	defer r.Unlock()
+	r.Lock()
+	if r.o != nil {
+		r.o.wake()
+	}
+}
@@ -0,0 +1,138 @@
+package whatsapp
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/gorilla/websocket"
+)
+
+type statusUpdate struct {
+	id     string
+	status MessageStatus
+	err    error
+}
+
+func TestOutbox(t *testing.T) {
+	store, err := NewFileOutboxStore(t.TempDir())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var updates []statusUpdate
+	onStatus := func(id string, status MessageStatus, err error) {
+		updates = append(updates, statusUpdate{id, status, err})
+	}
+
+	wac := &Conn{Store: newStore(), listener: &listenerWrapper{m: make(map[string]chan string)}}
+	o, err := NewOutbox(wac, OutboxOptions{Store: store, RetryInterval: time.Hour, MaxAttempts: 2, OnStatus: onStatus})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	first, err := o.Send(TextMessage{Info: MessageInfo{RemoteJid: "491701111111@s.whatsapp.net"}, Text: "a"}, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	second, err := o.Send(TextMessage{Info: MessageInfo{RemoteJid: "491702222222@s.whatsapp.net"}, Text: "b"}, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if p := o.Pending(); len(p) != 2 || p[0] != first || p[1] != second {
+		t.Errorf("unexpected pending messages %v", p)
+	}
+
+	// the connection is not logged in, so the messages stay pending and are loaded by a new outbox
+	o.flush()
+	o.Close()
+	// closing twice is a no-op
+	o.Close()
+	o, err = NewOutbox(wac, OutboxOptions{Store: store, RetryInterval: time.Hour, MaxAttempts: 2, OnStatus: onStatus})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer o.Close()
+	if p := o.Pending(); len(p) != 2 || p[0] != first || p[1] != second {
+		t.Fatalf("unexpected loaded messages %v", p)
+	}
+
+	due := o.due(time.Now())
+	if len(due) != 2 {
+		t.Fatalf("expected 2 due messages, got %d", len(due))
+	}
+
+	// a timeout is retried with the same message, a refused message fails
+	o.result(due[0], &TimeoutError{Op: "sending message"})
+	o.result(due[1], &ServerError{Op: "message sending", Status: 400})
+	if s, _ := o.Status(first); s != Pending {
+		t.Errorf("expected timed out message to be pending, got %d", s)
+	}
+	if len(o.due(time.Now())) != 0 {
+		t.Error("expected no due messages before the retry interval")
+	}
+
+	third, err := o.Send(TextMessage{Info: MessageInfo{RemoteJid: "491703333333@s.whatsapp.net"}, Text: "c"}, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	o.result(due[0], nil)
+	frames := []string{
+		// the server ack of the retried message is a duplicate
+		`["Msg",{"cmd":"ack","id":"` + first + `","ack":1,"from":"4900000@c.us","to":"491701111111@c.us","t":1600000000}]`,
+		`["Msg",{"cmd":"ack","id":"` + first + `","ack":2,"from":"491701111111@c.us","to":"4900000@c.us","t":1600000001}]`,
+		`["Msg",{"cmd":"ack","id":"` + first + `","ack":2,"from":"491701111111@c.us","to":"4900000@c.us","t":1600000001}]`,
+		`["MsgInfo",{"cmd":"acks","id":["` + first + `","` + second + `"],"ack":3,"from":"491701111111@c.us","to":"4900000@c.us","t":1600000002}]`,
+		`["Msg",{"cmd":"ack","id":"` + first + `","ack":4,"from":"491701111111@c.us","to":"4900000@c.us","t":1600000003}]`,
+		// a pending message is acknowledged by the ack frame
+		`["Msg",{"cmd":"ack","id":"` + third + `","ack":1,"from":"4900000@c.us","to":"491703333333@c.us","t":1600000004}]`,
+		`["Msg",{"cmd":"ack","id":"` + third + `","ack":-1,"from":"4900000@c.us","to":"491703333333@c.us","t":1600000005}]`,
+	}
+	for i, f := range frames {
+		if err := wac.processReadData(websocket.TextMessage, []byte(fmt.Sprintf("%d.--%d,%s", 1600000000, i, f))); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	expected := []statusUpdate{
+		{second, Error, nil},
+		{first, ServerAck, nil},
+		{first, DeliveryAck, nil},
+		{first, Read, nil},
+		{first, Played, nil},
+		{third, ServerAck, nil},
+		{third, Error, nil},
+	}
+	if len(updates) != len(expected) {
+		t.Fatalf("unexpected status updates %v", updates)
+	}
+	for i, u := range updates {
+		if u.id != expected[i].id || u.status != expected[i].status || (u.status == Error) != (u.err != nil) {
+			t.Errorf("update %d: got %v, want %v", i, u, expected[i])
+		}
+	}
+
+	if msgs, err := store.Load(); err != nil || len(msgs) != 0 {
+		t.Errorf("expected empty store, got %d messages, %v", len(msgs), err)
+	}
+}
+
+func TestRetryable(t *testing.T) {
+	tests := []struct {
+		err  error
+		want bool
+	}{
+		{&TimeoutError{Op: "sending message"}, true},
+		{ErrInvalidWebsocket, true},
+		{&ServerError{Status: 429}, true},
+		{&ServerError{Status: 503}, true},
+		{&ServerError{Status: 400}, false},
+		{&ServerError{Status: 401}, false},
+	}
+	for _, tt := range tests {
+		if got := retryable(tt.err); got != tt.want {
+			t.Errorf("retryable(%v) = %v, want %v", tt.err, got, tt.want)
+		}
+	}
+}
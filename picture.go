@@ -0,0 +1,144 @@
+package whatsapp
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"image"
+	"image/color"
+	"image/jpeg"
+	_ "image/png"
+	"io/ioutil"
+	"net/http"
+	"time"
+)
+
+const (
+	profilePicSize     = 640
+	profilePreviewSize = 96
+)
+
+// ProfilePicture contains the location of a profile picture. Id changes whenever the picture is changed.
+type ProfilePicture struct {
+	Jid string
+	URL string
+	Id  string
+}
+
+// Download retrieves the image data of the profile picture.
+func (p ProfilePicture) Download() ([]byte, error) {
+	resp, err := http.Get(p.URL)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, &ServerError{Op: "profile picture download", Status: resp.StatusCode}
+	}
+	return ioutil.ReadAll(resp.Body)
+}
+
+/*
+ProfilePicture returns the profile picture of a user or group. If full is set the full size picture is returned,
+otherwise the preview thumbnail. ErrNoProfilePicture is returned if there is no picture or it is not visible to the
+account.
+*/
+func (wac *Conn) ProfilePicture(jid string, full bool) (ProfilePicture, error) {
+	var ch <-chan string
+	var err error
+	if full {
+		ch, err = wac.writeJson([]interface{}{"query", "ProfilePic", jid})
+	} else {
+		ch, err = wac.GetProfilePicThumb(jid)
+	}
+	if err != nil {
+		return ProfilePicture{}, err
+	}
+
+	var resp struct {
+		Status int    `json:"status"`
+		EURL   string `json:"eurl"`
+		Tag    string `json:"tag"`
+	}
+	select {
+	case r := <-ch:
+		if err := json.Unmarshal([]byte(r), &resp); err != nil {
+			return ProfilePicture{}, fmt.Errorf("error decoding profile picture response: %v", err)
+		}
+	case <-time.After(wac.msgTimeout):
+		return ProfilePicture{}, &TimeoutError{Op: "profile picture request"}
+	}
+
+	if resp.Status == http.StatusNotFound || resp.Status == http.StatusUnauthorized || (resp.Status == 0 && resp.EURL == "") {
+		return ProfilePicture{}, ErrNoProfilePicture
+	}
+	if resp.Status != 0 && resp.Status != http.StatusOK {
+		return ProfilePicture{}, &ServerError{Op: "profile picture request", Status: resp.Status}
+	}
+
+	return ProfilePicture{Jid: jid, URL: resp.EURL, Id: resp.Tag}, nil
+}
+
+/*
+squareJPEG decodes a JPEG or PNG image, crops it to a centered square and scales it to size x size pixels. The result
+is encoded as JPEG.
+*/
+func squareJPEG(data []byte, size int) ([]byte, error) {
+	src, _, err := image.Decode(bytes.NewReader(data))
+	if err != nil {
+		return nil, fmt.Errorf("could not decode image: %v", err)
+	}
+
+	b := src.Bounds()
+	side := b.Dx()
+	if b.Dy() < side {
+		side = b.Dy()
+	}
+	if side == 0 {
+		return nil, fmt.Errorf("image is empty")
+	}
+	crop := image.Rect(0, 0, side, side).Add(image.Pt(b.Min.X+(b.Dx()-side)/2, b.Min.Y+(b.Dy()-side)/2))
+
+	dst := image.NewRGBA(image.Rect(0, 0, size, size))
+	for y := 0; y < size; y++ {
+		y0 := crop.Min.Y + y*side/size
+		y1 := crop.Min.Y + (y+1)*side/size
+		if y1 <= y0 {
+			y1 = y0 + 1
+		}
+		for x := 0; x < size; x++ {
+			x0 := crop.Min.X + x*side/size
+			x1 := crop.Min.X + (x+1)*side/size
+			if x1 <= x0 {
+				x1 = x0 + 1
+			}
+			dst.Set(x, y, averageColor(src, image.Rect(x0, y0, x1, y1)))
+		}
+	}
+
+	var buf bytes.Buffer
+	if err := jpeg.Encode(&buf, dst, &jpeg.Options{Quality: 90}); err != nil {
+		return nil, fmt.Errorf("could not encode image: %v", err)
+	}
+	return buf.Bytes(), nil
+}
+
+// averageColor returns the average color of the pixels of img within r.
+func averageColor(img image.Image, r image.Rectangle) color.RGBA {
+	var sr, sg, sb, n uint64
+	for y := r.Min.Y; y < r.Max.Y; y++ {
+		for x := r.Min.X; x < r.Max.X; x++ {
+			cr, cg, cb, _ := img.At(x, y).RGBA()
+			sr += uint64(cr)
+			sg += uint64(cg)
+			sb += uint64(cb)
+			n++
+		}
+	}
+	return color.RGBA{
+		R: uint8(sr / n >> 8),
+		G: uint8(sg / n >> 8),
+		B: uint8(sb / n >> 8),
+		A: 0xff,
+	}
+}
@@ -0,0 +1,51 @@
+package whatsapp
+
+import (
+	"bytes"
+	"image"
+	"image/color"
+	"image/jpeg"
+	"image/png"
+	"testing"
+)
+
+func TestSquareJPEG(t *testing.T) {
+	// 300x100 image with a red center square between white borders
+	src := image.NewRGBA(image.Rect(0, 0, 300, 100))
+	for y := 0; y < 100; y++ {
+		for x := 0; x < 300; x++ {
+			c := color.RGBA{R: 0xff, G: 0xff, B: 0xff, A: 0xff}
+			if x >= 100 && x < 200 {
+				c = color.RGBA{R: 0xff, A: 0xff}
+			}
+			src.Set(x, y, c)
+		}
+	}
+	var buf bytes.Buffer
+	if err := png.Encode(&buf, src); err != nil {
+		t.Fatal(err)
+	}
+
+	data, err := squareJPEG(buf.Bytes(), profilePreviewSize)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	dst, err := jpeg.Decode(bytes.NewReader(data))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if b := dst.Bounds(); b.Dx() != profilePreviewSize || b.Dy() != profilePreviewSize {
+		t.Fatalf("unexpected size %v", b)
+	}
+	for _, p := range []image.Point{{2, 2}, {48, 48}, {93, 93}} {
+		r, g, b, _ := dst.At(p.X, p.Y).RGBA()
+		if r>>8 < 0xe0 || g>>8 > 0x20 || b>>8 > 0x20 {
+			t.Errorf("pixel %v is not red after cropping: %d %d %d", p, r>>8, g>>8, b>>8)
+		}
+	}
+
+	if _, err := squareJPEG([]byte("no image"), profilePicSize); err == nil {
+		t.Error("expected error for invalid image data")
+	}
+}
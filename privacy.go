@@ -0,0 +1,182 @@
+package whatsapp
+
+import (
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/Rhymen/go-whatsapp/binary"
+)
+
+// PrivacySetting is the name of a privacy setting of the account.
+type PrivacySetting string
+
+const (
+	PrivacyLastSeen     PrivacySetting = "last"
+	PrivacyProfilePhoto PrivacySetting = "profile"
+	PrivacyAbout        PrivacySetting = "status"
+	PrivacyReadReceipts PrivacySetting = "readreceipts"
+	PrivacyGroupAdd     PrivacySetting = "groupadd"
+)
+
+/*
+PrivacyValue is the value of a privacy setting. PrivacyReadReceipts only supports PrivacyAll and PrivacyNobody,
+PrivacyContactsExcept is only supported by PrivacyGroupAdd.
+*/
+type PrivacyValue string
+
+const (
+	PrivacyAll            PrivacyValue = "all"
+	PrivacyContacts       PrivacyValue = "contacts"
+	PrivacyContactsExcept PrivacyValue = "contact_blacklist"
+	PrivacyNobody         PrivacyValue = "none"
+)
+
+// PrivacySettings contains the privacy settings of the account.
+type PrivacySettings struct {
+	LastSeen     PrivacyValue
+	ProfilePhoto PrivacyValue
+	About        PrivacyValue
+	ReadReceipts PrivacyValue
+	GroupAdd     PrivacyValue
+}
+
+// StatusPrivacyMode determines who can see the status updates posted by the account.
+type StatusPrivacyMode string
+
+const (
+	// StatusPrivacyContacts shares status updates with all contacts.
+	StatusPrivacyContacts StatusPrivacyMode = "contacts"
+	// StatusPrivacyBlacklist shares status updates with all contacts except the listed ones.
+	StatusPrivacyBlacklist StatusPrivacyMode = "blacklist"
+	// StatusPrivacyWhitelist shares status updates only with the listed contacts.
+	StatusPrivacyWhitelist StatusPrivacyMode = "whitelist"
+)
+
+// StatusPrivacy contains the status privacy mode and the deny or allow list of the mode.
+type StatusPrivacy struct {
+	Mode StatusPrivacyMode
+	Jids []string
+}
+
+// PrivacySettings queries the privacy settings of the account.
+func (wac *Conn) PrivacySettings() (PrivacySettings, error) {
+	node, err := wac.query("privacy", "", "", "", "", "", 0, 0)
+	if err != nil {
+		return PrivacySettings{}, err
+	}
+
+	var settings PrivacySettings
+	for _, n := range nodeChildren(node.Content) {
+		if n.Description != "category" {
+			continue
+		}
+		value := PrivacyValue(n.Attributes["value"])
+		switch PrivacySetting(n.Attributes["name"]) {
+		case PrivacyLastSeen:
+			settings.LastSeen = value
+		case PrivacyProfilePhoto:
+			settings.ProfilePhoto = value
+		case PrivacyAbout:
+			settings.About = value
+		case PrivacyReadReceipts:
+			settings.ReadReceipts = value
+		case PrivacyGroupAdd:
+			settings.GroupAdd = value
+		}
+	}
+
+	return settings, nil
+}
+
+// SetPrivacySetting changes a single privacy setting of the account.
+func (wac *Conn) SetPrivacySetting(setting PrivacySetting, value PrivacyValue) error {
+	n := binary.Node{
+		Description: "category",
+		Attributes: map[string]string{
+			"name":  string(setting),
+			"value": string(value),
+		},
+	}
+	return wac.setPrivacy(n, "set privacy "+string(setting))
+}
+
+// StatusPrivacy queries who can see the status updates posted by the account.
+func (wac *Conn) StatusPrivacy() (StatusPrivacy, error) {
+	node, err := wac.query("privacy", "", "", "status", "", "", 0, 0)
+	if err != nil {
+		return StatusPrivacy{}, err
+	}
+
+	privacy := StatusPrivacy{Mode: StatusPrivacyContacts}
+	for _, n := range nodeChildren(node.Content) {
+		if n.Description != "status" {
+			continue
+		}
+		if mode := n.Attributes["type"]; mode != "" {
+			privacy.Mode = StatusPrivacyMode(mode)
+		}
+		for _, user := range nodeChildren(n.Content) {
+			if user.Description == "user" {
+				privacy.Jids = append(privacy.Jids, toSWhatsAppJid(user.Attributes["jid"]))
+			}
+		}
+	}
+
+	return privacy, nil
+}
+
+/*
+SetStatusPrivacy changes who can see the status updates posted by the account. Jids are ignored for
+StatusPrivacyContacts, for the other modes they replace the current deny or allow list.
+*/
+func (wac *Conn) SetStatusPrivacy(privacy StatusPrivacy) error {
+	n := binary.Node{
+		Description: "status",
+		Attributes: map[string]string{
+			"type": string(privacy.Mode),
+		},
+	}
+	if privacy.Mode != StatusPrivacyContacts {
+		n.Content = buildUserNodes(privacy.Jids)
+	}
+	return wac.setPrivacy(n, "set status privacy")
+}
+
+func buildUserNodes(jids []string) []binary.Node {
+	users := make([]binary.Node, len(jids))
+	for i, jid := range jids {
+		users[i] = binary.Node{
+			Description: "user",
+			Attributes: map[string]string{
+				"jid": jid,
+			},
+		}
+	}
+	return users
+}
+
+func (wac *Conn) setPrivacy(content binary.Node, op string) error {
+	ts := time.Now().Unix()
+	tag := fmt.Sprintf("%d.--%d", ts, wac.msgCount)
+
+	n := binary.Node{
+		Description: "action",
+		Attributes: map[string]string{
+			"type":  "set",
+			"epoch": strconv.Itoa(wac.msgCount),
+		},
+		Content: []interface{}{
+			binary.Node{
+				Description: "privacy",
+				Content:     []binary.Node{content},
+			},
+		},
+	}
+
+	ch, err := wac.writeBinary(n, privacyStatus, ackRequest, tag)
+	if err != nil {
+		return fmt.Errorf("could not send proto: %v", err)
+	}
+	return wac.waitChatResponse(ch, op)
+}
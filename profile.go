@@ -8,8 +8,27 @@ import (
 	"github.com/Rhymen/go-whatsapp/binary"
 )
 
-// Pictures must be JPG 640x640 and 96x96, respectively
+/*
+UploadProfilePic changes the profile picture. JPEG and PNG images are accepted, they are cropped to a centered square
+and scaled to 640x640 (image) and 96x96 (preview) JPEGs. If preview is nil, it is created from image.
+*/
 func (wac *Conn) UploadProfilePic(image, preview []byte) (<-chan string, error) {
+	return wac.uploadPicture(wac.Info.Wid, image, preview, profile)
+}
+
+func (wac *Conn) uploadPicture(jid string, image, preview []byte, metric metric) (<-chan string, error) {
+	if preview == nil {
+		preview = image
+	}
+	image, err := squareJPEG(image, profilePicSize)
+	if err != nil {
+		return nil, err
+	}
+	preview, err = squareJPEG(preview, profilePreviewSize)
+	if err != nil {
+		return nil, err
+	}
+
 	tag := fmt.Sprintf("%d.--%d", time.Now().Unix(), wac.msgCount*19)
 	n := binary.Node{
 		Description: "action",
@@ -22,7 +41,7 @@ func (wac *Conn) UploadProfilePic(image, preview []byte) (<-chan string, error)
 				Description: "picture",
 				Attributes: map[string]string{
 					"id":   tag,
-					"jid":  wac.Info.Wid,
+					"jid":  jid,
 					"type": "set",
 				},
 				Content: []binary.Node{
@@ -40,7 +59,7 @@ func (wac *Conn) UploadProfilePic(image, preview []byte) (<-chan string, error)
 			},
 		},
 	}
-	return wac.writeBinary(n, profile, 136, tag)
+	return wac.writeBinary(n, metric, 136, tag)
 }
 
 func (wac *Conn) UpdateProfileName(name string) (<-chan string, error) {
@@ -63,3 +82,55 @@ func (wac *Conn) UpdateProfileName(name string) (<-chan string, error) {
 	}
 	return wac.writeBinary(n, profile, ignore, tag)
 }
+
+// SetAbout changes the about text of the account.
+func (wac *Conn) SetAbout(text string) error {
+	tag := fmt.Sprintf("%d.--%d", time.Now().Unix(), wac.msgCount)
+	n := binary.Node{
+		Description: "action",
+		Attributes: map[string]string{
+			"type":  "set",
+			"epoch": strconv.Itoa(wac.msgCount),
+		},
+		Content: []interface{}{
+			binary.Node{
+				Description: "status",
+				Content:     []byte(text),
+			},
+		},
+	}
+
+	ch, err := wac.writeBinary(n, status, ackRequest, tag)
+	if err != nil {
+		return fmt.Errorf("could not send proto: %v", err)
+	}
+	return wac.waitChatResponse(ch, "set about")
+}
+
+// RemoveProfilePicture removes the profile picture of the account.
+func (wac *Conn) RemoveProfilePicture() error {
+	tag := fmt.Sprintf("%d.--%d", time.Now().Unix(), wac.msgCount*19)
+	n := binary.Node{
+		Description: "action",
+		Attributes: map[string]string{
+			"type":  "set",
+			"epoch": strconv.Itoa(wac.msgCount),
+		},
+		Content: []interface{}{
+			binary.Node{
+				Description: "picture",
+				Attributes: map[string]string{
+					"id":   tag,
+					"jid":  wac.Info.Wid,
+					"type": "delete",
+				},
+			},
+		},
+	}
+
+	ch, err := wac.writeBinary(n, pic, 136, tag)
+	if err != nil {
+		return fmt.Errorf("could not send proto: %v", err)
+	}
+	return wac.waitChatResponse(ch, "remove profile picture")
+}
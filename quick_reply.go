@@ -0,0 +1,118 @@
+package whatsapp
+
+import (
+	"strings"
+
+	"github.com/Rhymen/go-whatsapp/binary"
+)
+
+/*
+QuickReply is a WhatsApp Business quick reply. The Message is inserted when typing a slash followed by the Shortcut.
+*/
+type QuickReply struct {
+	Id       string
+	Shortcut string
+	Message  string
+	Keywords []string
+}
+
+/*
+QuickReplyEvent is dispatched when quick replies are added, changed or deleted, e.g. on the phone.
+*/
+type QuickReplyEvent struct {
+	Deleted    bool
+	QuickReply QuickReply
+}
+
+func getQuickReply(msg binary.Node) QuickReply {
+	qr := QuickReply{
+		Id:       msg.Attributes["id"],
+		Shortcut: strings.TrimPrefix(msg.Attributes["shortcut"], "/"),
+		Message:  msg.Attributes["message"],
+	}
+
+	for _, n := range nodeChildren(msg.Content) {
+		if n.Description != "keyword" {
+			continue
+		}
+		switch k := n.Content.(type) {
+		case []byte:
+			qr.Keywords = append(qr.Keywords, string(k))
+		case string:
+			qr.Keywords = append(qr.Keywords, k)
+		}
+	}
+
+	return qr
+}
+
+func getQuickReplyEvent(msg binary.Node) QuickReplyEvent {
+	return QuickReplyEvent{
+		Deleted:    msg.Attributes["type"] == "delete",
+		QuickReply: getQuickReply(msg),
+	}
+}
+
+// QuickReplies queries the quick replies of the account and stores them in the Store.
+func (wac *Conn) QuickReplies() ([]QuickReply, error) {
+	node, err := wac.query("quick_reply", "", "", "", "", "", 0, 0)
+	if err != nil {
+		return nil, err
+	}
+
+	var replies []QuickReply
+	for _, n := range nodeChildren(node.Content) {
+		if n.Description == "quick_reply" {
+			replies = append(replies, getQuickReply(n))
+		}
+	}
+
+	wac.Store.setQuickReplies(replies)
+	return replies, nil
+}
+
+/*
+ExpandQuickReply replaces every word of the text that consists of a slash followed by the shortcut of a quick reply in
+the Store with the message of that quick reply. Shortcuts are matched case-insensitively.
+*/
+func (sr *Store) ExpandQuickReply(text string) string {
+	if !strings.Contains(text, "/") {
+		return text
+	}
+
+	sr.RLock()
+	defer sr.RUnlock()
+
+	if len(sr.QuickReplies) == 0 {
+		return text
+	}
+
+	var b strings.Builder
+	start := -1
+	flush := func(end int) {
+		word := text[start:end]
+		if qr, ok := sr.QuickReplies[strings.ToLower(word[1:])]; ok {
+			b.WriteString(qr.Message)
+		} else {
+			b.WriteString(word)
+		}
+		start = -1
+	}
+
+	for i, r := range text {
+		switch {
+		case start >= 0 && (r == ' ' || r == '\n' || r == '\t'):
+			flush(i)
+			b.WriteRune(r)
+		case start < 0 && r == '/' && (i == 0 || strings.ContainsAny(text[i-1:i], " \n\t")):
+			start = i
+		case start < 0:
+			b.WriteRune(r)
+		}
+	}
+	if start >= 0 {
+		flush(len(text))
+	}
+
+	return b.String()
+}
@@ -4,7 +4,6 @@ import (
 	"crypto/hmac"
 	"crypto/sha256"
 	"encoding/json"
-	"fmt"
 	"io"
 	"io/ioutil"
 	"net/http"
@@ -37,16 +36,19 @@ func (wac *Conn) readPump() {
 		select {
 		case <-readerFound:
 			if readErr != nil {
+				wac.log(LogError, "reading from websocket failed", "err", readErr)
 				wac.handle(&ErrConnectionFailed{Err: readErr})
 				return
 			}
 			msg, err := ioutil.ReadAll(reader)
 			if err != nil {
+				wac.log(LogWarn, "reading message failed", "err", err)
 				wac.handle(errors.Wrap(err, "error reading message from Reader"))
 				continue
 			}
 			err = wac.processReadData(msgType, msg)
 			if err != nil {
+				wac.log(LogWarn, "processing message failed", "err", err)
 				wac.handle(errors.Wrap(err, "error processing data"))
 			}
 		case <-wac.ws.close:
@@ -56,6 +58,9 @@ func (wac *Conn) readPump() {
 }
 
 func (wac *Conn) processReadData(msgType int, msg []byte) error {
+	wac.count(MetricFramesIn, 1, "type", frameType(msgType))
+	wac.count(MetricBytesIn, int64(len(msg)))
+
 	data := strings.SplitN(string(msg), ",", 2)
 
 	if data[0][0] == '!' { //Keep-Alive Timestamp
@@ -76,6 +81,8 @@ func (wac *Conn) processReadData(msgType int, msg []byte) error {
 	wac.listener.RUnlock()
 
 	if hasListener {
+		wac.responseReceived(data[0])
+		wac.recordResponse(msgType == websocket.BinaryMessage, data[0], data[1])
 		// listener only exists for TextMessages query messages out of contact.go
 		// If these binary query messages can be handled another way,
 		// then the TextMessages, which are all JSON encoded, can directly
@@ -92,18 +99,34 @@ func (wac *Conn) processReadData(msgType int, msg []byte) error {
 		if sess == nil || sess.MacKey == nil || sess.EncKey == nil {
 			return ErrInvalidWsState
 		}
-		message, err := wac.decryptBinaryMessage([]byte(data[1]))
+		d, err := wac.decryptBinaryData([]byte(data[1]))
 		if err != nil {
 			return errors.Wrap(err, "error decoding binary")
 		}
+		wac.record(Frame{Direction: FrameIn, Tag: data[0], Binary: d})
+		message, err := unmarshalBinaryMessage(d)
+		if err != nil {
+			return errors.Wrap(err, "error decoding binary")
+		}
+		wac.logNode("received", data[0], message)
 		wac.dispatch(message)
 	} else { //RAW json status updates
-		wac.handle(string(data[1]))
+		wac.record(Frame{Direction: FrameIn, Tag: data[0], Json: data[1]})
+		wac.dispatch(string(data[1]))
 	}
 	return nil
 }
 
 func (wac *Conn) decryptBinaryMessage(msg []byte) (*binary.Node, error) {
+	d, err := wac.decryptBinaryData(msg)
+	if err != nil {
+		return nil, err
+	}
+	return unmarshalBinaryMessage(d)
+}
+
+// decryptBinaryData validates and decrypts a binary message without decoding the node.
+func (wac *Conn) decryptBinaryData(msg []byte) ([]byte, error) {
 	//message validation
 	h2 := hmac.New(sha256.New, wac.session.MacKey)
 	if len(msg) < 33 {
@@ -112,10 +135,11 @@ func (wac *Conn) decryptBinaryMessage(msg []byte) (*binary.Node, error) {
 		}
 
 		if err := json.Unmarshal(msg, &response); err == nil {
+			// the sentinel is kept for callers comparing with ==, errors.Is matches both
 			if response.Status == http.StatusNotFound {
 				return nil, ErrServerRespondedWith404
 			}
-			return nil, errors.New(fmt.Sprintf("server responded with %d", response.Status))
+			return nil, &ServerError{Op: "query", Status: response.Status}
 		}
 
 		return nil, ErrInvalidServerResponse
@@ -123,15 +147,21 @@ func (wac *Conn) decryptBinaryMessage(msg []byte) (*binary.Node, error) {
 	}
 	h2.Write([]byte(msg[32:]))
 	if !hmac.Equal(h2.Sum(nil), msg[:32]) {
+		wac.count(MetricHmacFailures, 1)
 		return nil, ErrInvalidHmac
 	}
 
 	// message decrypt
 	d, err := cbc.Decrypt(wac.session.EncKey, nil, msg[32:])
 	if err != nil {
+		wac.count(MetricDecryptFailures, 1)
 		return nil, errors.Wrap(err, "decrypting message with AES-CBC failed")
 	}
 
+	return d, nil
+}
+
+func unmarshalBinaryMessage(d []byte) (*binary.Node, error) {
 	// message unmarshal
 	message, err := binary.Unmarshal(d)
 	if err != nil {
@@ -0,0 +1,70 @@
+package whatsapp
+
+import (
+	"encoding/json"
+	"strings"
+	"time"
+)
+
+/*
+ReceiptEvent is dispatched when the server acknowledges messages or recipients received, read or played them. For
+messages sent to groups and broadcast lists Participant is the recipient the receipt is from.
+*/
+type ReceiptEvent struct {
+	Ids         []string
+	RemoteJid   string
+	Participant string
+	Status      MessageStatus
+	Timestamp   time.Time
+}
+
+type receiptResponse struct {
+	Cmd         string          `json:"cmd"`
+	Id          json.RawMessage `json:"id"`
+	Ack         int             `json:"ack"`
+	From        string          `json:"from"`
+	To          string          `json:"to"`
+	Participant string          `json:"participant"`
+	T           int64           `json:"t"`
+}
+
+// parseReceipt parses ["Msg",{"cmd":"ack",...}] and ["MsgInfo",{"cmd":"acks",...}] json messages.
+func parseReceipt(msg string) (ReceiptEvent, bool) {
+	if !strings.HasPrefix(msg, `["Msg"`) && !strings.HasPrefix(msg, `["MsgInfo"`) {
+		return ReceiptEvent{}, false
+	}
+
+	var resp []json.RawMessage
+	if err := json.Unmarshal([]byte(msg), &resp); err != nil || len(resp) < 2 {
+		return ReceiptEvent{}, false
+	}
+
+	var r receiptResponse
+	if err := json.Unmarshal(resp[1], &r); err != nil || (r.Cmd != "ack" && r.Cmd != "acks") {
+		return ReceiptEvent{}, false
+	}
+
+	event := ReceiptEvent{
+		RemoteJid:   toSWhatsAppJid(r.From),
+		Participant: toSWhatsAppJid(r.Participant),
+		Status:      ackStatus(r.Ack),
+		Timestamp:   unixTime(r.T),
+	}
+
+	var id string
+	if err := json.Unmarshal(r.Id, &id); err == nil {
+		event.Ids = []string{id}
+	} else if err := json.Unmarshal(r.Id, &event.Ids); err != nil {
+		return ReceiptEvent{}, false
+	}
+
+	return event, true
+}
+
+// ackStatus converts a WhatsApp Web ack (-1 error, 1 sent, 2 delivered, 3 read, 4 played) to a MessageStatus.
+func ackStatus(ack int) MessageStatus {
+	if ack < 0 {
+		return Error
+	}
+	return MessageStatus(ack + 1)
+}
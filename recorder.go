@@ -0,0 +1,179 @@
+package whatsapp
+
+import (
+	"encoding/json"
+	"io"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/Rhymen/go-whatsapp/binary"
+)
+
+const (
+	FrameIn  = "in"
+	FrameOut = "out"
+)
+
+/*
+Frame is a websocket frame in a recording. Binary frames contain the decrypted binary node as Binary, which can be
+decoded with binary.Unmarshal, and a readable summary as Node. Response is set for incoming frames answering a request
+of the connection, these are not dispatched when replayed.
+*/
+type Frame struct {
+	Time      time.Time `json:"time"`
+	Direction string    `json:"dir"`
+	Tag       string    `json:"tag"`
+	Json      string    `json:"json,omitempty"`
+	Binary    []byte    `json:"binary,omitempty"`
+	Node      string    `json:"node,omitempty"`
+	Metric    int       `json:"metric,omitempty"`
+	Flag      int       `json:"flag,omitempty"`
+	Response  bool      `json:"response,omitempty"`
+}
+
+/*
+Recorder writes the frames sent and received by a connection as json lines, see Options.Recorder. The recording
+contains the decrypted traffic including message contents and should be handled like the session.
+*/
+type Recorder struct {
+	sync.Mutex
+	enc *json.Encoder
+	c   io.Closer
+}
+
+// NewRecorder creates a recorder writing to w.
+func NewRecorder(w io.Writer) *Recorder {
+	return &Recorder{enc: json.NewEncoder(w)}
+}
+
+// CreateRecording creates a recorder writing to a new file at path.
+func CreateRecording(path string) (*Recorder, error) {
+	f, err := os.OpenFile(path, os.O_CREATE|os.O_TRUNC|os.O_WRONLY, 0600)
+	if err != nil {
+		return nil, err
+	}
+	r := NewRecorder(f)
+	r.c = f
+	return r, nil
+}
+
+// Record writes the frame to the recording.
+func (r *Recorder) Record(f Frame) error {
+	if f.Time.IsZero() {
+		f.Time = time.Now()
+	}
+	defer r.Unlock()
+	r.Lock()
+	return r.enc.Encode(f)
+}
+
+// Close closes the file of a recording created with CreateRecording.
+func (r *Recorder) Close() error {
+	if r.c == nil {
+		return nil
+	}
+	return r.c.Close()
+}
+
+func (wac *Conn) record(f Frame) {
+	if wac.recorder == nil {
+		return
+	}
+	if f.Binary != nil {
+		if n, err := binary.Unmarshal(f.Binary); err == nil {
+			f.Node = redactNode(*n)
+		}
+	}
+	if err := wac.recorder.Record(f); err != nil {
+		wac.log(LogWarn, "recording frame failed", "err", err)
+	}
+}
+
+func (wac *Conn) recordSent(node binary.Node, metric metric, flag flag, tag string) {
+	if wac.recorder == nil {
+		return
+	}
+	data, err := binary.Marshal(node)
+	if err != nil {
+		wac.log(LogWarn, "recording frame failed", "err", err)
+		return
+	}
+	wac.record(Frame{Direction: FrameOut, Tag: tag, Binary: data, Metric: int(metric), Flag: int(flag)})
+}
+
+// recordResponse records a frame answering a request, binary responses are decrypted if possible.
+func (wac *Conn) recordResponse(binaryFrame bool, tag string, data string) {
+	if wac.recorder == nil {
+		return
+	}
+	f := Frame{Direction: FrameIn, Tag: tag, Response: true}
+	if !binaryFrame || wac.session == nil {
+		f.Json = data
+	} else if d, err := wac.decryptBinaryData([]byte(data)); err == nil {
+		f.Binary = d
+	} else {
+		f.Json = data
+	}
+	wac.record(f)
+}
+
+/*
+Replayer dispatches the incoming frames of a recording to the handlers of a connection that is not connected to the
+servers, so the handling of recorded traffic can be reproduced e.g. in tests. Handlers should be called synchronously
+for a deterministic order.
+*/
+type Replayer struct {
+	Conn *Conn
+	dec  *json.Decoder
+}
+
+/*
+NewReplayer creates a replayer for the recording read from r. The connection is created with the given options
+without connecting, handlers can be passed in the options or added to Replayer.Conn.
+*/
+func NewReplayer(opt *Options, r io.Reader) (*Replayer, error) {
+	if opt == nil {
+		return nil, ErrOptionsNotProvided
+	}
+	return &Replayer{
+		Conn: newConn(opt),
+		dec:  json.NewDecoder(r),
+	}, nil
+}
+
+/*
+Next reads the next frame of the recording and dispatches it if it is an incoming frame that is no response. io.EOF
+is returned at the end of the recording.
+*/
+func (r *Replayer) Next() (Frame, error) {
+	var f Frame
+	if err := r.dec.Decode(&f); err != nil {
+		return f, err
+	}
+	if f.Direction != FrameIn || f.Response {
+		return f, nil
+	}
+
+	if f.Binary != nil {
+		node, err := binary.Unmarshal(f.Binary)
+		if err != nil {
+			return f, err
+		}
+		r.Conn.dispatch(node)
+	} else {
+		r.Conn.dispatch(f.Json)
+	}
+	return f, nil
+}
+
+// Run dispatches all frames of the recording.
+func (r *Replayer) Run() error {
+	for {
+		if _, err := r.Next(); err == io.EOF {
+			return nil
+		} else if err != nil {
+			return err
+		}
+	}
+}
@@ -0,0 +1,51 @@
+package whatsapp
+
+import (
+	"bytes"
+	"context"
+	"testing"
+
+	"github.com/Rhymen/go-whatsapp/binary"
+	"github.com/Rhymen/go-whatsapp/binary/proto"
+)
+
+func TestRecordReplay(t *testing.T) {
+	jid, id, text := "a@s.whatsapp.net", "1", "hi"
+	data, err := binary.Marshal(binary.Node{
+		Description: "action",
+		Attributes:  map[string]string{"add": "relay"},
+		Content: []interface{}{&proto.WebMessageInfo{
+			Key:     &proto.MessageKey{RemoteJid: &jid, Id: &id},
+			Message: &proto.Message{Conversation: &text},
+		}},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var buf bytes.Buffer
+	wac := &Conn{recorder: NewRecorder(&buf)}
+	wac.record(Frame{Direction: FrameOut, Tag: "1.--0", Json: `["query","exist","a@c.us"]`})
+	wac.record(Frame{Direction: FrameIn, Tag: "1.--0", Json: `{"status":200}`, Response: true})
+	wac.record(Frame{Direction: FrameIn, Tag: "2", Binary: data})
+	wac.record(Frame{Direction: FrameIn, Tag: "3", Json: `["Blocklist",{"id":1,"blocklist":["b@c.us"]}]`})
+
+	r, err := NewReplayer(&Options{}, &buf)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var texts []string
+	var blocked []string
+	On(r.Conn, func(ctx context.Context, m TextMessage) { texts = append(texts, m.Text) }, Synchronously())
+	On(r.Conn, func(ctx context.Context, e BlockListChangedEvent) { blocked = e.Jids }, Synchronously())
+
+	if err := r.Run(); err != nil {
+		t.Fatal(err)
+	}
+	if len(texts) != 1 || texts[0] != "hi" {
+		t.Errorf("unexpected texts %v", texts)
+	}
+	if len(blocked) != 1 || !r.Conn.Store.IsBlocked(blocked[0]) {
+		t.Errorf("unexpected block list %v", blocked)
+	}
+}
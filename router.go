@@ -0,0 +1,310 @@
+package whatsapp
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+	"sync"
+)
+
+// ChatScope restricts a Command to private chats or groups.
+type ChatScope int
+
+const (
+	AnyChat ChatScope = iota
+	PrivateChat
+	GroupChat
+)
+
+func (s ChatScope) allows(jid string) bool {
+	switch s {
+	case PrivateChat:
+		return !isGroupJid(jid)
+	case GroupChat:
+		return isGroupJid(jid)
+	}
+	return true
+}
+
+func isGroupJid(jid string) bool {
+	return strings.HasSuffix(jid, "@g.us")
+}
+
+/*
+Command is a bot command handled by a Router. The command is called by sending a prefix followed by Name or one of
+the Aliases, optionally followed by arguments, e.g. "!remind 10m tea".
+
+If Args is set the arguments must match it, otherwise the usage is sent as reply. Scope, AllowedSenders and
+AdminOnly restrict who may call the command, AdminOnly requires the sender to be an admin of the group and only
+allows the command in groups.
+*/
+type Command struct {
+	Name        string
+	Aliases     []string
+	Description string
+	// Usage describes the arguments in the help text, e.g. "<duration> <text>".
+	Usage          string
+	Args           *regexp.Regexp
+	Scope          ChatScope
+	AllowedSenders []string
+	AdminOnly      bool
+	// Hidden commands are not listed in the help text.
+	Hidden  bool
+	Handler func(ctx context.Context, req *CommandRequest) error
+}
+
+/*
+CommandRequest is a call of a Command. Args is the text after the command name, Fields the whitespace separated
+arguments and Matches the submatches of Command.Args, starting with the whole argument text.
+*/
+type CommandRequest struct {
+	Message TextMessage
+	Command *Command
+	Prefix  string
+	Name    string
+	Args    string
+	Fields  []string
+	Matches []string
+	Router  *Router
+}
+
+// Chat returns the jid of the chat the command was sent in.
+func (req *CommandRequest) Chat() string {
+	return req.Message.Info.RemoteJid
+}
+
+// Sender returns the jid of the user that sent the command.
+func (req *CommandRequest) Sender() string {
+	return req.Message.Info.Sender()
+}
+
+// Reply sends a text message quoting the command to the chat the command was sent in.
+func (req *CommandRequest) Reply(text string) error {
+	return req.Router.reply(req.Message, text)
+}
+
+/*
+Router dispatches text messages starting with one of its prefixes to the registered commands. Messages sent by the
+account itself are ignored, as well as messages replayed after an offline period or loaded from the history unless
+HandleReplayed is set. If HelpName is not empty, a help command listing the commands available to the sender is
+answered automatically.
+*/
+type Router struct {
+	wac      *Conn
+	prefixes []string
+	sub      *Subscription
+
+	sync.RWMutex
+	commands map[string]*Command
+
+	// HelpName is the name of the automatic help command, "help" by default.
+	HelpName string
+	// Denied is sent as reply if the sender is not allowed to call a command. No reply is sent if it is empty.
+	Denied string
+	// NotFound is called for messages with a prefix but an unknown command.
+	NotFound func(ctx context.Context, req *CommandRequest) error
+	// HandleReplayed enables handling commands that were not received live, see EventOrigin.
+	HandleReplayed bool
+}
+
+/*
+NewRouter creates a router for the commands with the given prefixes, e.g. "!" or "/", and subscribes it to the text
+messages of the connection. "!" is used if no prefix is given.
+*/
+func NewRouter(wac *Conn, prefixes ...string) *Router {
+	if len(prefixes) == 0 {
+		prefixes = []string{"!"}
+	}
+	r := &Router{
+		wac:      wac,
+		prefixes: prefixes,
+		commands: make(map[string]*Command),
+		HelpName: "help",
+	}
+	r.sub = On(wac, r.handleTextMessage)
+	return r
+}
+
+// Handle registers the command. A command with the same name or alias is replaced.
+func (r *Router) Handle(cmd Command) {
+	defer r.Unlock()
+	r.Lock()
+	for _, name := range append([]string{cmd.Name}, cmd.Aliases...) {
+		r.commands[strings.ToLower(name)] = &cmd
+	}
+}
+
+// Close unsubscribes the router from the messages of the connection.
+func (r *Router) Close() {
+	r.sub.Unsubscribe()
+}
+
+func (r *Router) lookup(name string) *Command {
+	defer r.RUnlock()
+	r.RLock()
+	return r.commands[strings.ToLower(name)]
+}
+
+// parse splits a text into prefix, command name and arguments.
+func (r *Router) parse(text string) (prefix, name, args string, ok bool) {
+	text = strings.TrimSpace(text)
+	for _, p := range r.prefixes {
+		if !strings.HasPrefix(text, p) {
+			continue
+		}
+		rest := text[len(p):]
+		if i := strings.IndexFunc(rest, isSpace); i >= 0 {
+			name, args = rest[:i], strings.TrimSpace(rest[i:])
+		} else {
+			name = rest
+		}
+		if name == "" {
+			continue
+		}
+		return p, name, args, true
+	}
+	return "", "", "", false
+}
+
+func isSpace(r rune) bool {
+	return r == ' ' || r == '\t' || r == '\n'
+}
+
+func (r *Router) handleTextMessage(ctx context.Context, m TextMessage) {
+	if m.Info.FromMe || (m.Info.Origin != OriginLive && !r.HandleReplayed) {
+		return
+	}
+	prefix, name, args, ok := r.parse(m.Text)
+	if !ok {
+		return
+	}
+
+	req := &CommandRequest{
+		Message: m,
+		Prefix:  prefix,
+		Name:    name,
+		Args:    args,
+		Fields:  strings.Fields(args),
+		Router:  r,
+	}
+	if err := r.route(ctx, req); err != nil {
+		r.wac.reportDispatchError(fmt.Errorf("command %s%s failed: %v", prefix, name, err))
+	}
+}
+
+func (r *Router) route(ctx context.Context, req *CommandRequest) error {
+	cmd := r.lookup(req.Name)
+	if cmd == nil {
+		if r.HelpName != "" && strings.EqualFold(req.Name, r.HelpName) {
+			return req.Reply(r.help(req))
+		}
+		if r.NotFound != nil {
+			return r.NotFound(ctx, req)
+		}
+		return nil
+	}
+	req.Command = cmd
+
+	allowed, err := r.allowed(cmd, req.Chat(), req.Sender())
+	if err != nil {
+		return err
+	}
+	if !allowed {
+		if r.Denied != "" {
+			return req.Reply(r.Denied)
+		}
+		return nil
+	}
+
+	if cmd.Args != nil {
+		req.Matches = cmd.Args.FindStringSubmatch(req.Args)
+		if req.Matches == nil {
+			return req.Reply(fmt.Sprintf("Usage: %s%s %s", req.Prefix, cmd.Name, cmd.Usage))
+		}
+	}
+
+	return cmd.Handler(ctx, req)
+}
+
+// allowed checks the restrictions of the command for the sender in the chat.
+func (r *Router) allowed(cmd *Command, chat, sender string) (bool, error) {
+	if !cmd.Scope.allows(chat) {
+		return false, nil
+	}
+	if len(cmd.AllowedSenders) > 0 && !containsString(cmd.AllowedSenders, sender) {
+		return false, nil
+	}
+	if cmd.AdminOnly {
+		if !isGroupJid(chat) {
+			return false, nil
+		}
+		g, err := r.wac.GroupInfo(chat)
+		if err != nil {
+			return false, err
+		}
+		p, ok := g.Participant(sender)
+		return ok && p.IsAdmin, nil
+	}
+	return true, nil
+}
+
+// help lists the commands the sender of the request is allowed to call.
+func (r *Router) help(req *CommandRequest) string {
+	r.RLock()
+	var commands []*Command
+	seen := make(map[*Command]bool)
+	for _, cmd := range r.commands {
+		if !seen[cmd] && !cmd.Hidden {
+			seen[cmd] = true
+			commands = append(commands, cmd)
+		}
+	}
+	r.RUnlock()
+
+	sort.Slice(commands, func(i, j int) bool {
+		return commands[i].Name < commands[j].Name
+	})
+
+	var b strings.Builder
+	b.WriteString("Commands:")
+	for _, cmd := range commands {
+		if ok, err := r.allowed(cmd, req.Chat(), req.Sender()); err != nil || !ok {
+			continue
+		}
+		b.WriteString("\n" + req.Prefix + cmd.Name)
+		if cmd.Usage != "" {
+			b.WriteString(" " + cmd.Usage)
+		}
+		if cmd.Description != "" {
+			b.WriteString(" - " + cmd.Description)
+		}
+	}
+	return b.String()
+}
+
+func (r *Router) reply(m TextMessage, text string) error {
+	reply := TextMessage{
+		Info: MessageInfo{
+			RemoteJid: m.Info.RemoteJid,
+		},
+		Text: text,
+		ContextInfo: ContextInfo{
+			QuotedMessageID: m.Info.Id,
+			QuotedMessage:   m.Info.Source.GetMessage(),
+			Participant:     m.Info.Sender(),
+		},
+	}
+	_, err := r.wac.Send(reply)
+	return err
+}
+
+func containsString(s []string, v string) bool {
+	for _, x := range s {
+		if x == v {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,56 @@
+package whatsapp
+
+import (
+	"context"
+	"regexp"
+	"strings"
+	"testing"
+)
+
+func TestRouter(t *testing.T) {
+	r := NewRouter(&Conn{}, "!", "/")
+	defer r.Close()
+
+	var calls []*CommandRequest
+	handler := func(ctx context.Context, req *CommandRequest) error {
+		calls = append(calls, req)
+		return nil
+	}
+	r.Handle(Command{
+		Name:    "remind",
+		Aliases: []string{"r"},
+		Usage:   "<minutes> <text>",
+		Args:    regexp.MustCompile(`^(\d+) (.+)$`),
+		Handler: handler,
+	})
+	r.Handle(Command{
+		Name:           "secret",
+		Scope:          PrivateChat,
+		AllowedSenders: []string{"boss@s.whatsapp.net"},
+		Handler:        handler,
+	})
+
+	private := MessageInfo{RemoteJid: "boss@s.whatsapp.net"}
+	group := MessageInfo{RemoteJid: "g@g.us", SenderJid: "boss@s.whatsapp.net"}
+
+	r.handleTextMessage(context.Background(), TextMessage{Info: private, Text: "/R 10 buy tea"})
+	r.handleTextMessage(context.Background(), TextMessage{Info: group, Text: "!secret"})
+	r.handleTextMessage(context.Background(), TextMessage{Info: private, Text: " !secret  now "})
+	r.handleTextMessage(context.Background(), TextMessage{Info: private, Text: "secret"})
+	r.handleTextMessage(context.Background(), TextMessage{Info: MessageInfo{RemoteJid: "boss@s.whatsapp.net", FromMe: true}, Text: "!secret"})
+
+	if len(calls) != 2 {
+		t.Fatalf("expected 2 calls, got %d", len(calls))
+	}
+	if c := calls[0]; c.Command.Name != "remind" || c.Prefix != "/" || len(c.Matches) != 3 || c.Matches[1] != "10" || c.Matches[2] != "buy tea" {
+		t.Errorf("unexpected request %+v", c)
+	}
+	if c := calls[1]; c.Command.Name != "secret" || c.Args != "now" || len(c.Fields) != 1 {
+		t.Errorf("unexpected request %+v", c)
+	}
+
+	help := r.help(&CommandRequest{Message: TextMessage{Info: group}, Prefix: "!"})
+	if !strings.Contains(help, "!remind <minutes> <text>") || strings.Contains(help, "secret") {
+		t.Errorf("unexpected help text %q", help)
+	}
+}
@@ -118,7 +118,7 @@ func CheckCurrentServerVersion() ([]int, error) {
 	select {
 	case r = <-loginChan:
 	case <-time.After(wac.msgTimeout):
-		return nil, fmt.Errorf("login connection timed out")
+		return nil, &TimeoutError{Op: "login connection"}
 	}
 
 	var resp map[string]interface{}
@@ -223,7 +223,7 @@ func (wac *Conn) Login(qrChan chan<- string) (Session, error) {
 	select {
 	case r = <-loginChan:
 	case <-time.After(wac.msgTimeout):
-		return session, fmt.Errorf("login connection timed out")
+		return session, &TimeoutError{Op: "login connection"}
 	}
 
 	var resp map[string]interface{}
@@ -260,7 +260,7 @@ func (wac *Conn) Login(qrChan chan<- string) (Session, error) {
 			return session, fmt.Errorf("error decoding qr code resp: %v", err)
 		}
 	case <-time.After(time.Duration(resp["ttl"].(float64)) * time.Millisecond):
-		return session, fmt.Errorf("qr code scan timed out")
+		return session, &TimeoutError{Op: "qr code scan"}
 	}
 
 	info := resp2[1].(map[string]interface{})
@@ -315,6 +315,9 @@ func (wac *Conn) Login(qrChan chan<- string) (Session, error) {
 	session.MacKey = keyDecrypted[32:64]
 	wac.session = &session
 	wac.loggedIn = true
+	wac.loginTime = time.Now()
+	wac.outbox.wake()
+	wac.log(LogInfo, "logged in", "wid", session.Wid)
 
 	return session, nil
 }
@@ -394,13 +397,13 @@ func (wac *Conn) Restore() error {
 			return fmt.Errorf("error decoding login connResp: %v\n", err)
 		}
 
-		if int(resp["status"].(float64)) != 200 {
+		if err := checkStatus("init", jsonStatus(resp)); err != nil {
 			wac.timeTag = ""
-			return fmt.Errorf("init responded with %d", resp["status"])
+			return err
 		}
 	case <-time.After(wac.msgTimeout):
 		wac.timeTag = ""
-		return fmt.Errorf("restore session init timed out")
+		return &TimeoutError{Op: "restore session init"}
 	}
 
 	//wait for s1
@@ -420,12 +423,12 @@ func (wac *Conn) Restore() error {
 			if err = json.Unmarshal([]byte(r), &resp); err != nil {
 				return fmt.Errorf("error decoding login connResp: %v\n", err)
 			}
-			if int(resp["status"].(float64)) != 200 {
-				return fmt.Errorf("admin login responded with %d", int(resp["status"].(float64)))
+			if err := checkStatus("admin login", jsonStatus(resp)); err != nil {
+				return err
 			}
 		default:
 			// not even an error message – assume timeout
-			return fmt.Errorf("restore session connection timed out")
+			return &TimeoutError{Op: "restore session connection"}
 		}
 	}
 
@@ -449,7 +452,7 @@ func (wac *Conn) Restore() error {
 			}
 		case <-time.After(wac.msgTimeout):
 			wac.timeTag = ""
-			return fmt.Errorf("restore session challenge timed out")
+			return &TimeoutError{Op: "restore session challenge"}
 		}
 	}
 
@@ -462,13 +465,13 @@ func (wac *Conn) Restore() error {
 			return fmt.Errorf("error decoding login connResp: %v\n", err)
 		}
 
-		if int(resp["status"].(float64)) != 200 {
+		if err := checkStatus("admin login", jsonStatus(resp)); err != nil {
 			wac.timeTag = ""
-			return fmt.Errorf("admin login responded with %d", resp["status"])
+			return err
 		}
 	case <-time.After(wac.msgTimeout):
 		wac.timeTag = ""
-		return fmt.Errorf("restore session login timed out")
+		return &TimeoutError{Op: "restore session login"}
 	}
 
 	info := connResp[1].(map[string]interface{})
@@ -480,6 +483,9 @@ func (wac *Conn) Restore() error {
 	wac.session.ServerToken = info["serverToken"].(string)
 	wac.session.Wid = info["wid"].(string)
 	wac.loggedIn = true
+	wac.loginTime = time.Now()
+	wac.outbox.wake()
+	wac.log(LogInfo, "session restored", "wid", wac.session.Wid)
 
 	return nil
 }
@@ -505,11 +511,11 @@ func (wac *Conn) resolveChallenge(challenge string) error {
 		if err := json.Unmarshal([]byte(r), &resp); err != nil {
 			return fmt.Errorf("error decoding login resp: %v\n", err)
 		}
-		if int(resp["status"].(float64)) != 200 {
-			return fmt.Errorf("challenge responded with %d\n", resp["status"])
+		if err := checkStatus("challenge", jsonStatus(resp)); err != nil {
+			return err
 		}
 	case <-time.After(wac.msgTimeout):
-		return fmt.Errorf("connection timed out")
+		return ErrConnectionTimeout
 	}
 
 	return nil
@@ -0,0 +1,115 @@
+package whatsapp
+
+import (
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/Rhymen/go-whatsapp/binary"
+	"github.com/Rhymen/go-whatsapp/binary/proto"
+)
+
+// MessageKey identifies a message within a chat.
+type MessageKey struct {
+	Id     string
+	FromMe bool
+}
+
+// Key returns the MessageKey of the message.
+func (info MessageInfo) Key() MessageKey {
+	return MessageKey{Id: info.Id, FromMe: info.FromMe}
+}
+
+/*
+StarEvent is dispatched when messages are starred or unstarred, e.g. on the phone.
+*/
+type StarEvent struct {
+	RemoteJid string
+	Keys      []MessageKey
+	Starred   bool
+}
+
+func getStarEvent(msg binary.Node) StarEvent {
+	event := StarEvent{
+		RemoteJid: toSWhatsAppJid(msg.Attributes["jid"]),
+		Starred:   msg.Attributes["type"] == "star",
+	}
+
+	for _, item := range nodeChildren(msg.Content) {
+		if item.Description != "item" {
+			continue
+		}
+		event.Keys = append(event.Keys, MessageKey{
+			Id:     item.Attributes["index"],
+			FromMe: item.Attributes["owner"] == "true",
+		})
+	}
+
+	return event
+}
+
+// StarMessages stars the messages of the chat.
+func (wac *Conn) StarMessages(jid string, keys ...MessageKey) error {
+	return wac.starMessages("star", jid, keys)
+}
+
+// UnstarMessages removes the star from the messages of the chat.
+func (wac *Conn) UnstarMessages(jid string, keys ...MessageKey) error {
+	return wac.starMessages("unstar", jid, keys)
+}
+
+func (wac *Conn) starMessages(t, jid string, keys []MessageKey) error {
+	if len(keys) == 0 {
+		return nil
+	}
+
+	ts := time.Now().Unix()
+	tag := fmt.Sprintf("%d.--%d", ts, wac.msgCount)
+
+	items := make([]binary.Node, len(keys))
+	for i, key := range keys {
+		items[i] = binary.Node{
+			Description: "item",
+			Attributes: map[string]string{
+				"owner": strconv.FormatBool(key.FromMe),
+				"index": key.Id,
+			},
+		}
+	}
+
+	n := binary.Node{
+		Description: "action",
+		Attributes: map[string]string{
+			"type":  "set",
+			"epoch": strconv.Itoa(wac.msgCount),
+		},
+		Content: []interface{}{
+			binary.Node{
+				Description: "chat",
+				Attributes: map[string]string{
+					"type": t,
+					"jid":  jid,
+				},
+				Content: items,
+			},
+		},
+	}
+
+	ch, err := wac.writeBinary(n, group, ackRequest, tag)
+	if err != nil {
+		return fmt.Errorf("could not send proto: %v", err)
+	}
+	return wac.waitChatResponse(ch, t+" messages")
+}
+
+/*
+StarredMessages loads the starred messages of all chats. Messages are loaded in pages of count messages, starting with
+page 1.
+*/
+func (wac *Conn) StarredMessages(count, page int) ([]*proto.WebMessageInfo, error) {
+	node, err := wac.query("star", "", "", "", "", "", count, page)
+	if err != nil {
+		return nil, err
+	}
+	return decodeMessages(node), nil
+}
@@ -0,0 +1,33 @@
+package whatsapp
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/Rhymen/go-whatsapp/binary"
+)
+
+func TestGetStarEvent(t *testing.T) {
+	n := binary.Node{
+		Description: "star",
+		Attributes:  map[string]string{"type": "star", "jid": "4912345@c.us"},
+		Content: []interface{}{
+			binary.Node{Description: "item", Attributes: map[string]string{"index": "3EB0AB", "owner": "true"}},
+			binary.Node{Description: "item", Attributes: map[string]string{"index": "3EB0CD", "owner": "false"}},
+		},
+	}
+
+	event := getStarEvent(n)
+	if event.RemoteJid != "4912345@s.whatsapp.net" || !event.Starred {
+		t.Errorf("unexpected star event %+v", event)
+	}
+	if want := []MessageKey{{Id: "3EB0AB", FromMe: true}, {Id: "3EB0CD"}}; !reflect.DeepEqual(event.Keys, want) {
+		t.Errorf("unexpected keys %v, want %v", event.Keys, want)
+	}
+
+	n.Attributes["type"] = "unstar"
+	n.Content = []binary.Node{{Description: "item", Attributes: map[string]string{"index": "3EB0AB", "owner": "true"}}}
+	if event := getStarEvent(n); event.Starred || len(event.Keys) != 1 {
+		t.Errorf("unexpected unstar event %+v", event)
+	}
+}
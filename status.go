@@ -0,0 +1,137 @@
+package whatsapp
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/Rhymen/go-whatsapp/binary"
+	"github.com/Rhymen/go-whatsapp/binary/proto"
+)
+
+// StatusBroadcastJid is the jid status updates are sent to.
+const StatusBroadcastJid = "status@broadcast"
+
+/*
+TextStatus is a text status update. Colors are ARGB values, e.g. 0xFF128C7E for an opaque background.
+*/
+type TextStatus struct {
+	Text            string
+	BackgroundColor uint32
+	TextColor       uint32
+	Font            proto.ExtendedTextMessage_ExtendedTextMessageFontType
+}
+
+/*
+StatusUpdateEvent is dispatched for status updates posted by contacts. Message is the TextMessage, ImageMessage or
+VideoMessage of the status update. Status updates are dispatched as regular messages as well.
+*/
+type StatusUpdateEvent struct {
+	Info    MessageInfo
+	Poster  string
+	Message interface{}
+}
+
+func getStatusUpdateEvent(msg *proto.WebMessageInfo, parsed interface{}) StatusUpdateEvent {
+	return StatusUpdateEvent{
+		Info:    getMessageInfo(msg),
+		Poster:  toSWhatsAppJid(msg.GetParticipant()),
+		Message: parsed,
+	}
+}
+
+// PostTextStatus posts a text status update, which is shared with the contacts allowed by the status privacy settings.
+func (wac *Conn) PostTextStatus(status TextStatus) (string, error) {
+	p := getInfoProto(&MessageInfo{RemoteJid: StatusBroadcastJid})
+	p.Message = &proto.Message{
+		ExtendedTextMessage: &proto.ExtendedTextMessage{
+			Text:           &status.Text,
+			BackgroundArgb: &status.BackgroundColor,
+			TextArgb:       &status.TextColor,
+			Font:           &status.Font,
+		},
+	}
+	return wac.postStatus(p)
+}
+
+// PostImageStatus posts an image status update, which is shared with the contacts allowed by the status privacy settings.
+func (wac *Conn) PostImageStatus(msg ImageMessage) (string, error) {
+	msg.Info.RemoteJid = StatusBroadcastJid
+	return wac.postStatus(msg)
+}
+
+// PostVideoStatus posts a video status update, which is shared with the contacts allowed by the status privacy settings.
+func (wac *Conn) PostVideoStatus(msg VideoMessage) (string, error) {
+	msg.Info.RemoteJid = StatusBroadcastJid
+	return wac.postStatus(msg)
+}
+
+func (wac *Conn) postStatus(msg interface{}) (string, error) {
+	audience, err := wac.statusAudience()
+	if err != nil {
+		return "ERROR", fmt.Errorf("could not determine status audience: %v", err)
+	}
+	return wac.send(msg, audience)
+}
+
+// statusAudience returns the jids that may see status updates according to the status privacy settings.
+func (wac *Conn) statusAudience() ([]string, error) {
+	privacy, err := wac.StatusPrivacy()
+	if err != nil {
+		return nil, err
+	}
+
+	own := toSWhatsAppJid(wac.session.Wid)
+	audience := []string{own}
+	if privacy.Mode == StatusPrivacyWhitelist {
+		return append(audience, privacy.Jids...), nil
+	}
+
+	excluded := make(map[string]bool)
+	if privacy.Mode == StatusPrivacyBlacklist {
+		for _, jid := range privacy.Jids {
+			excluded[jid] = true
+		}
+	}
+
+	for jid, contact := range wac.Store.GetContacts() {
+		// only saved contacts have a name
+		if contact.Name == "" || jid == own || excluded[jid] || !strings.HasSuffix(jid, "@s.whatsapp.net") {
+			continue
+		}
+		audience = append(audience, jid)
+	}
+
+	return audience, nil
+}
+
+// MarkStatusViewed sends a read receipt for the status update to its poster.
+func (wac *Conn) MarkStatusViewed(status StatusUpdateEvent) error {
+	ts := time.Now().Unix()
+	tag := fmt.Sprintf("%d.--%d", ts, wac.msgCount)
+
+	n := binary.Node{
+		Description: "action",
+		Attributes: map[string]string{
+			"type":  "set",
+			"epoch": strconv.Itoa(wac.msgCount),
+		},
+		Content: []interface{}{binary.Node{
+			Description: "read",
+			Attributes: map[string]string{
+				"count":       "1",
+				"index":       status.Info.Id,
+				"jid":         StatusBroadcastJid,
+				"participant": status.Poster,
+				"owner":       "false",
+			},
+		}},
+	}
+
+	_, err := wac.writeBinary(n, group, ignore, tag)
+	if err != nil {
+		return fmt.Errorf("could not send read receipt: %v", err)
+	}
+	return nil
+}
@@ -10,6 +10,13 @@ import (
 type Store struct {
 	Contacts map[string]Contact
 	Chats    map[string]Chat
+	Groups   map[string]GroupMetadata
+	Labels   map[string]Label
+	// ChatLabels contains the ids of the labels assigned to a chat.
+	ChatLabels map[string][]string
+	// QuickReplies contains the quick replies by their lowercase shortcut.
+	QuickReplies map[string]QuickReply
+	Blocked      map[string]bool
 	sync.RWMutex
 }
 
@@ -27,12 +34,19 @@ type Chat struct {
 	LastMessageTime string
 	IsMuted         string
 	IsMarkedSpam    string
+	IsArchived      string
+	IsPinned        string
 }
 
 func newStore() *Store {
 	return &Store{
 		make(map[string]Contact),
 		make(map[string]Chat),
+		make(map[string]GroupMetadata),
+		make(map[string]Label),
+		make(map[string][]string),
+		make(map[string]QuickReply),
+		make(map[string]bool),
 		sync.RWMutex{},
 	}
 }
@@ -118,6 +132,8 @@ func (sr *Store) updateChats(chats interface{}) {
 			chatNode.Attributes["t"],
 			chatNode.Attributes["mute"],
 			chatNode.Attributes["spam"],
+			chatNode.Attributes["archive"],
+			chatNode.Attributes["pin"],
 		}
 	}
 }
@@ -156,3 +172,242 @@ func (sr *Store) AddChat(chat Chat) error {
 
 	return nil
 }
+
+func (sr *Store) GetGroupMetadata(jid string) (GroupMetadata, bool) {
+
+	defer sr.RUnlock()
+	sr.RLock()
+
+	if group, ok := sr.Groups[jid]; ok {
+		return group, ok
+	}
+
+	return GroupMetadata{}, false
+}
+
+func (sr *Store) setGroupMetadata(group GroupMetadata) {
+	defer sr.Unlock()
+	sr.Lock()
+
+	if sr.Groups == nil {
+		sr.Groups = make(map[string]GroupMetadata)
+	}
+	sr.Groups[group.Jid] = group
+}
+
+func (sr *Store) invalidateGroupMetadata(jid string) {
+	defer sr.Unlock()
+	sr.Lock()
+
+	delete(sr.Groups, jid)
+}
+
+// updateGroupMetadata applies a group event to the cached metadata. Membership changes invalidate the entry, as the
+// event does not contain all information about the new participants.
+func (sr *Store) updateGroupMetadata(event GroupEvent) {
+	defer sr.Unlock()
+	sr.Lock()
+
+	g, ok := sr.Groups[event.GroupJid]
+	if !ok {
+		return
+	}
+
+	switch event.Action {
+	case GroupActionSubject:
+		g.Subject = event.Value
+		g.SubjectOwner = event.Author
+	case GroupActionDescription:
+		g.Description = event.Value
+		g.DescriptionOwner = event.Author
+	case GroupActionAnnounce:
+		g.Announce = event.Value == "on" || event.Value == "true"
+	case GroupActionLocked:
+		g.Locked = event.Value == "on" || event.Value == "true"
+	case GroupActionPromote, GroupActionDemote:
+		participants := make([]GroupParticipant, len(g.Participants))
+		copy(participants, g.Participants)
+		for i, p := range participants {
+			for _, jid := range event.Participants {
+				if p.Jid == jid {
+					participants[i].IsAdmin = event.Action == GroupActionPromote
+					if event.Action == GroupActionDemote {
+						participants[i].IsSuperAdmin = false
+					}
+				}
+			}
+		}
+		g.Participants = participants
+	case GroupActionPicture, GroupActionInviteLink:
+		return
+	default:
+		delete(sr.Groups, event.GroupJid)
+		return
+	}
+
+	sr.Groups[event.GroupJid] = g
+}
+
+// updateChat applies fn to the stored chat with the given jid, if there is one.
+func (sr *Store) updateChat(jid string, fn func(chat *Chat)) {
+	defer sr.Unlock()
+	sr.Lock()
+
+	if chat, ok := sr.Chats[jid]; ok {
+		fn(&chat)
+		sr.Chats[jid] = chat
+	}
+}
+
+func (sr *Store) removeChat(jid string) {
+	defer sr.Unlock()
+	sr.Lock()
+
+	delete(sr.Chats, jid)
+}
+
+func (sr *Store) GetLabels() map[string]Label {
+
+	defer sr.RUnlock()
+	sr.RLock()
+
+	return sr.Labels
+}
+
+func (sr *Store) GetChatLabels(jid string) []string {
+
+	defer sr.RUnlock()
+	sr.RLock()
+
+	return sr.ChatLabels[jid]
+}
+
+func (sr *Store) setLabels(labels []Label) {
+	defer sr.Unlock()
+	sr.Lock()
+
+	sr.Labels = make(map[string]Label, len(labels))
+	for _, label := range labels {
+		sr.Labels[label.Id] = label
+	}
+}
+
+func (sr *Store) updateLabels(event LabelEvent) {
+	defer sr.Unlock()
+	sr.Lock()
+
+	if sr.Labels == nil {
+		sr.Labels = make(map[string]Label)
+	}
+	if sr.ChatLabels == nil {
+		sr.ChatLabels = make(map[string][]string)
+	}
+
+	switch event.Action {
+	case LabelAdded, LabelEdited:
+		sr.Labels[event.Label.Id] = event.Label
+	case LabelDeleted:
+		delete(sr.Labels, event.Label.Id)
+		for jid, ids := range sr.ChatLabels {
+			sr.ChatLabels[jid] = removeString(ids, event.Label.Id)
+		}
+	case LabelAssigned:
+		if event.Key == nil {
+			ids := removeString(sr.ChatLabels[event.Jid], event.Label.Id)
+			sr.ChatLabels[event.Jid] = append(ids, event.Label.Id)
+		}
+	case LabelUnassigned:
+		if event.Key == nil {
+			sr.ChatLabels[event.Jid] = removeString(sr.ChatLabels[event.Jid], event.Label.Id)
+		}
+	}
+}
+
+func removeString(list []string, s string) []string {
+	ret := make([]string, 0, len(list))
+	for _, v := range list {
+		if v != s {
+			ret = append(ret, v)
+		}
+	}
+	return ret
+}
+
+func (sr *Store) GetQuickReplies() map[string]QuickReply {
+
+	defer sr.RUnlock()
+	sr.RLock()
+
+	return sr.QuickReplies
+}
+
+func (sr *Store) setQuickReplies(replies []QuickReply) {
+	defer sr.Unlock()
+	sr.Lock()
+
+	sr.QuickReplies = make(map[string]QuickReply, len(replies))
+	for _, qr := range replies {
+		sr.QuickReplies[strings.ToLower(qr.Shortcut)] = qr
+	}
+}
+
+func (sr *Store) updateQuickReply(event QuickReplyEvent) {
+	defer sr.Unlock()
+	sr.Lock()
+
+	if sr.QuickReplies == nil {
+		sr.QuickReplies = make(map[string]QuickReply)
+	}
+
+	// the shortcut may have changed, so the previous entry is looked up by id
+	for shortcut, qr := range sr.QuickReplies {
+		if qr.Id == event.QuickReply.Id {
+			delete(sr.QuickReplies, shortcut)
+		}
+	}
+	if !event.Deleted {
+		sr.QuickReplies[strings.ToLower(event.QuickReply.Shortcut)] = event.QuickReply
+	}
+}
+
+func (sr *Store) GetBlockList() []string {
+
+	defer sr.RUnlock()
+	sr.RLock()
+
+	jids := make([]string, 0, len(sr.Blocked))
+	for jid := range sr.Blocked {
+		jids = append(jids, jid)
+	}
+	return jids
+}
+
+func (sr *Store) IsBlocked(jid string) bool {
+
+	defer sr.RUnlock()
+	sr.RLock()
+
+	return sr.Blocked[toSWhatsAppJid(jid)]
+}
+
+// setBlockList replaces the block list and returns the jids that were added and removed.
+func (sr *Store) setBlockList(jids []string) (added, removed []string) {
+	defer sr.Unlock()
+	sr.Lock()
+
+	blocked := make(map[string]bool, len(jids))
+	for _, jid := range jids {
+		blocked[jid] = true
+		if !sr.Blocked[jid] {
+			added = append(added, jid)
+		}
+	}
+	for jid := range sr.Blocked {
+		if !blocked[jid] {
+			removed = append(removed, jid)
+		}
+	}
+	sr.Blocked = blocked
+
+	return added, removed
+}
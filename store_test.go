@@ -0,0 +1,88 @@
+package whatsapp
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestStoreUpdateLabels(t *testing.T) {
+	sr := newStore()
+	sr.setLabels([]Label{{Id: "1", Name: "New customer", Color: 0}, {Id: "2", Name: "Paid", Color: 3}})
+
+	sr.updateLabels(LabelEvent{Action: LabelAssigned, Label: Label{Id: "1"}, Jid: "a@s.whatsapp.net"})
+	sr.updateLabels(LabelEvent{Action: LabelAssigned, Label: Label{Id: "2"}, Jid: "a@s.whatsapp.net"})
+	sr.updateLabels(LabelEvent{Action: LabelAssigned, Label: Label{Id: "1"}, Jid: "a@s.whatsapp.net"})
+	if labels := sr.GetChatLabels("a@s.whatsapp.net"); !reflect.DeepEqual(labels, []string{"2", "1"}) {
+		t.Errorf("unexpected chat labels %v", labels)
+	}
+
+	sr.updateLabels(LabelEvent{Action: LabelEdited, Label: Label{Id: "2", Name: "Paid", Color: 5}})
+	if sr.Labels["2"].Color != 5 {
+		t.Errorf("label was not edited: %+v", sr.Labels["2"])
+	}
+
+	sr.updateLabels(LabelEvent{Action: LabelDeleted, Label: Label{Id: "1"}})
+	if _, ok := sr.Labels["1"]; ok {
+		t.Error("label was not deleted")
+	}
+	if labels := sr.GetChatLabels("a@s.whatsapp.net"); !reflect.DeepEqual(labels, []string{"2"}) {
+		t.Errorf("deleted label still assigned: %v", labels)
+	}
+}
+
+func TestStoreDemoteSuperAdmin(t *testing.T) {
+	sr := newStore()
+	sr.setGroupMetadata(GroupMetadata{
+		Jid:          "123-456@g.us",
+		Participants: []GroupParticipant{{Jid: "a@s.whatsapp.net", IsAdmin: true, IsSuperAdmin: true}},
+	})
+
+	sr.updateGroupMetadata(GroupEvent{GroupJid: "123-456@g.us", Action: GroupActionDemote, Participants: []string{"a@s.whatsapp.net"}})
+	g, _ := sr.GetGroupMetadata("123-456@g.us")
+	if p := g.Participants[0]; p.IsAdmin || p.IsSuperAdmin {
+		t.Errorf("demoted participant is still an admin: %+v", p)
+	}
+}
+
+func TestStoreExpandQuickReply(t *testing.T) {
+	sr := newStore()
+	sr.setQuickReplies([]QuickReply{{Id: "1", Shortcut: "thanks", Message: "Thank you for your order!"}})
+
+	tests := map[string]string{
+		"/thanks":              "Thank you for your order!",
+		"Hi!\n/Thanks see you": "Hi!\nThank you for your order! see you",
+		"/unknown and a/b":     "/unknown and a/b",
+		"no shortcut":          "no shortcut",
+	}
+	for in, want := range tests {
+		if got := sr.ExpandQuickReply(in); got != want {
+			t.Errorf("ExpandQuickReply(%q) = %q, want %q", in, got, want)
+		}
+	}
+
+	sr.updateQuickReply(QuickReplyEvent{QuickReply: QuickReply{Id: "1", Shortcut: "ty", Message: "Thanks!"}})
+	if got := sr.ExpandQuickReply("/thanks /ty"); got != "/thanks Thanks!" {
+		t.Errorf("renamed shortcut was not applied: %q", got)
+	}
+}
+
+func TestParseBlockList(t *testing.T) {
+	jids, ok := parseBlockList(`["Blocklist",{"id":1,"blocklist":["4912345@c.us","4967890@c.us"]}]`)
+	if !ok || !reflect.DeepEqual(jids, []string{"4912345@s.whatsapp.net", "4967890@s.whatsapp.net"}) {
+		t.Fatalf("unexpected block list %v", jids)
+	}
+
+	sr := newStore()
+	sr.setBlockList(jids)
+	added, removed := sr.setBlockList([]string{"4967890@s.whatsapp.net", "4911111@s.whatsapp.net"})
+	if !reflect.DeepEqual(added, []string{"4911111@s.whatsapp.net"}) || !reflect.DeepEqual(removed, []string{"4912345@s.whatsapp.net"}) {
+		t.Errorf("unexpected changes: added %v, removed %v", added, removed)
+	}
+	if !sr.IsBlocked("4967890@c.us") || sr.IsBlocked("4912345@s.whatsapp.net") {
+		t.Error("IsBlocked does not match the block list")
+	}
+
+	if _, ok := parseBlockList(`["Presence",{"id":"4912345@c.us"}]`); ok {
+		t.Error("parsed a message that is no block list")
+	}
+}
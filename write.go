@@ -17,14 +17,23 @@ import (
 
 func (wac *Conn) addListener(ch chan string, messageTag string) {
 	wac.listener.Lock()
+	_, exists := wac.listener.m[messageTag]
 	wac.listener.m[messageTag] = ch
 	wac.listener.Unlock()
+	if !exists {
+		wac.count(MetricListeners, 1)
+	}
 }
 
 func (wac *Conn) removeListener(answerMessageTag string) {
 	wac.listener.Lock()
+	_, exists := wac.listener.m[answerMessageTag]
 	delete(wac.listener.m, answerMessageTag)
+	delete(wac.listener.requests, answerMessageTag)
 	wac.listener.Unlock()
+	if exists {
+		wac.count(MetricListeners, -1)
+	}
 }
 
 //writeJson enqueues a json message into the writeChan
@@ -51,6 +60,7 @@ func (wac *Conn) writeJson(data []interface{}) (<-chan string, error) {
 	}
 
 	wac.addListener(ch, messageTag)
+	wac.trackRequest(messageTag, "json")
 
 	err = wac.write(websocket.TextMessage, bytes)
 	if err != nil {
@@ -58,6 +68,8 @@ func (wac *Conn) writeJson(data []interface{}) (<-chan string, error) {
 		wac.removeListener(messageTag)
 		return ch, err
 	}
+	wac.log(LogDebug, "sent json", "tag", messageTag, "type", data[0])
+	wac.record(Frame{Direction: FrameOut, Tag: messageTag, Json: string(d)})
 
 	wac.msgCount++
 	return ch, nil
@@ -86,6 +98,7 @@ func (wac *Conn) writeBinary(node binary.Node, metric metric, flag flag, message
 	bytes = append(bytes, data...)
 
 	wac.addListener(ch, messageTag)
+	wac.trackRequest(messageTag, metric.String())
 
 	err = wac.write(websocket.BinaryMessage, bytes)
 	if err != nil {
@@ -93,6 +106,8 @@ func (wac *Conn) writeBinary(node binary.Node, metric metric, flag flag, message
 		wac.removeListener(messageTag)
 		return ch, errors.Wrap(err, "failed to write message")
 	}
+	wac.logNode("sent", messageTag, &node)
+	wac.recordSent(node, metric, flag, messageTag)
 
 	wac.msgCount++
 	return ch, nil
@@ -167,8 +182,11 @@ func (wac *Conn) write(messageType int, data []byte) error {
 	wac.ws.Unlock()
 
 	if err != nil {
+		wac.log(LogError, "writing to websocket failed", "err", err)
 		return errors.Wrap(err, "error writing to websocket")
 	}
+	wac.count(MetricFramesOut, 1, "type", frameType(messageType))
+	wac.count(MetricBytesOut, int64(len(data)))
 
 	return nil
 }
@@ -193,3 +211,10 @@ func (wac *Conn) encryptBinaryMessage(node binary.Node) (data []byte, err error)
 
 	return data, nil
 }
+
+func frameType(messageType int) string {
+	if messageType == websocket.BinaryMessage {
+		return "binary"
+	}
+	return "json"
+}